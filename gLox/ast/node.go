@@ -0,0 +1,410 @@
+package ast
+
+import "github.com/StanleyGY/Lox/gLox/token"
+
+// Node is implemented by every Stmt and Expr
+type Node interface {
+	// Span is the range of source text that the node is parsed from
+	Span() token.Span
+}
+
+type Stmt interface {
+	Node
+	Accept(v StmtVisitor) error
+}
+
+type Expr interface {
+	Node
+	Accept(v ExprVisitor) (interface{}, error)
+}
+
+// SrcRange is embedded in every node to record its source range
+type SrcRange struct {
+	Range token.Span
+}
+
+func (r *SrcRange) Span() token.Span {
+	return r.Range
+}
+
+func (r *SrcRange) SetSpan(span token.Span) {
+	r.Range = span
+}
+
+type InlineExprStmt struct {
+	SrcRange
+	Child Expr
+}
+
+func (e *InlineExprStmt) Accept(v StmtVisitor) error {
+	return v.VisitInlineExprStmt(e)
+}
+
+type PrintStmt struct {
+	SrcRange
+	Child Expr
+}
+
+func (e *PrintStmt) Accept(v StmtVisitor) error {
+	return v.VisitPrintStmt(e)
+}
+
+type VarDeclStmt struct {
+	SrcRange
+	Name        *token.Token
+	Initializer Expr
+	// Text of the "///" doc comments before the declaration
+	Doc string
+}
+
+func (e *VarDeclStmt) Accept(v StmtVisitor) error {
+	return v.VisitVarDeclStmt(e)
+}
+
+type FuncDeclStmt struct {
+	SrcRange
+	Name   *token.Token
+	Params []*token.Token
+	Body   Stmt
+	// Text of the "///" doc comments before the declaration
+	Doc string
+}
+
+func (e *FuncDeclStmt) Accept(v StmtVisitor) error {
+	return v.VisitFunDeclStmt(e)
+}
+
+type ClassDeclStmt struct {
+	SrcRange
+	Name       *token.Token
+	SuperClass *VariableExpr
+	Methods    []*FuncDeclStmt
+	// Text of the "///" doc comments before the declaration
+	Doc string
+}
+
+func (e *ClassDeclStmt) Accept(v StmtVisitor) error {
+	return v.VisitClassDeclStmt(e)
+}
+
+type IfStmt struct {
+	SrcRange
+	Condition  Expr
+	ThenBranch Stmt
+	ElseBranch Stmt
+}
+
+func (e *IfStmt) Accept(v StmtVisitor) error {
+	return v.VisitIfStmt(e)
+}
+
+// WhileStmt also represents a C-style for loop, whose `Increment` runs after each iteration,
+// even one ended by "continue". `Increment` is nil for a while loop.
+type WhileStmt struct {
+	SrcRange
+	// Optional label, which "break" and "continue" can target
+	Label     *token.Token
+	Condition Expr
+	Body      Stmt
+	Increment Expr
+}
+
+func (e *WhileStmt) Accept(v StmtVisitor) error {
+	return v.VisitWhileStmt(e)
+}
+
+// ForInStmt runs `Body` for each element of `Iterable`, bound to a new variable `Name`
+type ForInStmt struct {
+	SrcRange
+	// Optional label, which "break" and "continue" can target
+	Label    *token.Token
+	Name     *token.Token
+	Iterable Expr
+	Body     Stmt
+}
+
+func (e *ForInStmt) Accept(v StmtVisitor) error {
+	return v.VisitForInStmt(e)
+}
+
+type ReturnStmt struct {
+	SrcRange
+	Value Expr
+}
+
+func (e *ReturnStmt) Accept(v StmtVisitor) error {
+	return v.VisitReturnStmt(e)
+}
+
+// BreakStmt exits the innermost loop, or the enclosing loop named by `Label`
+type BreakStmt struct {
+	SrcRange
+	Label *token.Token
+}
+
+func (e *BreakStmt) Accept(v StmtVisitor) error {
+	return v.VisitBreakStmt(e)
+}
+
+// ContinueStmt starts the next iteration of the innermost loop, or of the enclosing loop named by `Label`
+type ContinueStmt struct {
+	SrcRange
+	Label *token.Token
+}
+
+func (e *ContinueStmt) Accept(v StmtVisitor) error {
+	return v.VisitContinueStmt(e)
+}
+
+type BlockStmt struct {
+	SrcRange
+	Stmts []Stmt
+}
+
+func (e *BlockStmt) Accept(v StmtVisitor) error {
+	return v.VisitBlockStmt(e)
+}
+
+type BinaryExpr struct {
+	SrcRange
+	Operator *token.Token
+	Left     Expr
+	Right    Expr
+}
+
+func (e *BinaryExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitBinaryExpr(e)
+}
+
+// LogicExpr differs from BinaryExpr in that it supports short-circuit
+// evaluation on its operands
+type LogicExpr struct {
+	SrcRange
+	Operator *token.Token
+	Left     Expr
+	Right    Expr
+}
+
+func (e *LogicExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitLogicalExpr(e)
+}
+
+type UnaryExpr struct {
+	SrcRange
+	Operator *token.Token
+	Right    Expr
+}
+
+func (e *UnaryExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitUnaryExpr(e)
+}
+
+type GroupingExpr struct {
+	SrcRange
+	Child Expr
+}
+
+func (e *GroupingExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitGroupingExpr(e)
+}
+
+type LiteralExpr struct {
+	SrcRange
+	Value interface{}
+}
+
+func (e *LiteralExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitLiteralExpr(e)
+}
+
+type VariableExpr struct {
+	SrcRange
+	Name *token.Token
+}
+
+func (e *VariableExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitVariableExpr(e)
+}
+
+type AssignExpr struct {
+	SrcRange
+	Name  *token.Token
+	Value Expr
+}
+
+func (e *AssignExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitAssignExpr(e)
+}
+
+type CallExpr struct {
+	SrcRange
+	Callee    Expr
+	Arguments []Expr
+}
+
+func (e *CallExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitCallExpr(e)
+}
+
+type GetPropertyExpr struct {
+	SrcRange
+	Object   Expr
+	Property *token.Token
+}
+
+func (e *GetPropertyExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitGetPropertyExpr(e)
+}
+
+type SetPropertyExpr struct {
+	SrcRange
+	Object   Expr
+	Property *token.Token
+	Value    Expr
+}
+
+func (e *SetPropertyExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitSetPropertyExpr(e)
+}
+
+type ThisExpr struct {
+	SrcRange
+}
+
+func (e *ThisExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitThisExpr(e)
+}
+
+type SuperExpr struct {
+	SrcRange
+	Property *token.Token
+}
+
+func (e *SuperExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitSuperExpr(e)
+}
+
+// ListExpr is a list literal, like [1, 2, 3]
+type ListExpr struct {
+	SrcRange
+	Elements []Expr
+}
+
+func (e *ListExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitListExpr(e)
+}
+
+// MapExpr is a map literal, like {"a": 1, "b": 2}.
+// `Keys` and `Values` are the entries in source order.
+type MapExpr struct {
+	SrcRange
+	Keys   []Expr
+	Values []Expr
+}
+
+func (e *MapExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitMapExpr(e)
+}
+
+// IndexGetExpr reads an element of a list, a string or a map, like xs[i]
+type IndexGetExpr struct {
+	SrcRange
+	Object Expr
+	Index  Expr
+}
+
+func (e *IndexGetExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitIndexGetExpr(e)
+}
+
+// IndexSetExpr writes an element of a list or a map, like xs[i] = v
+type IndexSetExpr struct {
+	SrcRange
+	Object Expr
+	Index  Expr
+	Value  Expr
+}
+
+func (e *IndexSetExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitIndexSetExpr(e)
+}
+
+// SliceExpr copies a range of a list or a string, like xs[1:3].
+// `Start` and `End` are nil when they are omitted.
+type SliceExpr struct {
+	SrcRange
+	Object Expr
+	Start  Expr
+	End    Expr
+}
+
+func (e *SliceExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitSliceExpr(e)
+}
+
+// UpdateExpr modifies a variable or a property with a binary operator,
+// like "x += 1", "obj.count -= 1", "++x" or "obj.count--"
+type UpdateExpr struct {
+	SrcRange
+	// Either a *VariableExpr, a *GetPropertyExpr or an *IndexGetExpr
+	Target Expr
+	// Binary operator applied on the target, like "+" for "+=" and "++".
+	// Its lexeme is the one written in the source.
+	Operator *token.Token
+	// Right operand of the operator, which is 1 for "++" and "--"
+	Value Expr
+	// Postfix "++" and "--" evaluate to the value before the update
+	Postfix bool
+}
+
+func (e *UpdateExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitUpdateExpr(e)
+}
+
+// InterpolationExpr is a string with embedded expressions, like "a ${b} c".
+// The string segments are kept in `Parts` as string literals.
+type InterpolationExpr struct {
+	SrcRange
+	Parts []Expr
+}
+
+func (e *InterpolationExpr) Accept(v ExprVisitor) (interface{}, error) {
+	return v.VisitInterpolationExpr(e)
+}
+
+type StmtVisitor interface {
+	VisitVarDeclStmt(stmt *VarDeclStmt) error
+	VisitFunDeclStmt(stmt *FuncDeclStmt) error
+	VisitClassDeclStmt(stmt *ClassDeclStmt) error
+	VisitInlineExprStmt(stmt *InlineExprStmt) error
+	VisitPrintStmt(stmt *PrintStmt) error
+	VisitBlockStmt(stmt *BlockStmt) error
+	VisitIfStmt(stmt *IfStmt) error
+	VisitWhileStmt(stmt *WhileStmt) error
+	VisitForInStmt(stmt *ForInStmt) error
+	VisitReturnStmt(stmt *ReturnStmt) error
+	VisitBreakStmt(stmt *BreakStmt) error
+	VisitContinueStmt(stmt *ContinueStmt) error
+}
+
+type ExprVisitor interface {
+	VisitBinaryExpr(expr *BinaryExpr) (interface{}, error)
+	VisitUnaryExpr(expr *UnaryExpr) (interface{}, error)
+	VisitLogicalExpr(expr *LogicExpr) (interface{}, error)
+	VisitGroupingExpr(expr *GroupingExpr) (interface{}, error)
+	VisitLiteralExpr(expr *LiteralExpr) (interface{}, error)
+	VisitAssignExpr(expr *AssignExpr) (interface{}, error)
+	VisitCallExpr(expr *CallExpr) (interface{}, error)
+	VisitGetPropertyExpr(expr *GetPropertyExpr) (interface{}, error)
+	VisitSetPropertyExpr(expr *SetPropertyExpr) (interface{}, error)
+	VisitVariableExpr(expr *VariableExpr) (interface{}, error)
+	VisitThisExpr(expr *ThisExpr) (interface{}, error)
+	VisitSuperExpr(expr *SuperExpr) (interface{}, error)
+	VisitInterpolationExpr(expr *InterpolationExpr) (interface{}, error)
+	VisitUpdateExpr(expr *UpdateExpr) (interface{}, error)
+	VisitListExpr(expr *ListExpr) (interface{}, error)
+	VisitMapExpr(expr *MapExpr) (interface{}, error)
+	VisitIndexGetExpr(expr *IndexGetExpr) (interface{}, error)
+	VisitIndexSetExpr(expr *IndexSetExpr) (interface{}, error)
+	VisitSliceExpr(expr *SliceExpr) (interface{}, error)
+}
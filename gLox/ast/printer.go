@@ -0,0 +1,293 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/token"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// TODO: make the printer more pretty
+type Printer struct {
+	buf bytes.Buffer
+}
+
+func (p *Printer) PrettyPrintExpr(expr Expr) string {
+	p.buf.Reset()
+	expr.Accept(p)
+	return p.buf.String()
+}
+
+func (p *Printer) PrettyPrintStmt(stmt Stmt) string {
+	p.buf.Reset()
+	stmt.Accept(p)
+	return p.buf.String()
+}
+
+func (p *Printer) parenthesis(name string, exprs ...Expr) {
+	p.buf.WriteString("(")
+	p.buf.WriteString(name)
+	if len(exprs) > 0 {
+		p.buf.WriteString(" ")
+	}
+	for idx, expr := range exprs {
+		expr.Accept(p)
+		if idx < len(exprs)-1 {
+			p.buf.WriteString(" ")
+		}
+	}
+	p.buf.WriteString(")")
+}
+
+func (p *Printer) VisitInlineExprStmt(stmt *InlineExprStmt) error {
+	stmt.Child.Accept(p)
+	return nil
+}
+
+func (p *Printer) VisitPrintStmt(stmt *PrintStmt) error {
+	p.parenthesis("print", stmt.Child)
+	return nil
+}
+
+func (p *Printer) VisitVarDeclStmt(stmt *VarDeclStmt) error {
+	if stmt.Initializer == nil {
+		p.parenthesis("assign", &LiteralExpr{Value: stmt.Name.Lexeme})
+		return nil
+	}
+	p.parenthesis("assign", &LiteralExpr{Value: stmt.Name.Lexeme}, stmt.Initializer)
+	return nil
+}
+
+func (p *Printer) VisitFunDeclStmt(stmt *FuncDeclStmt) error {
+	names := []Expr{&LiteralExpr{Value: stmt.Name.Lexeme}}
+	for _, param := range stmt.Params {
+		names = append(names, &LiteralExpr{Value: param.Lexeme})
+	}
+	p.buf.WriteString("(")
+	p.parenthesis("fun", names...)
+	stmt.Body.Accept(p)
+	p.buf.WriteString(")")
+	return nil
+}
+
+func (p *Printer) VisitClassDeclStmt(stmt *ClassDeclStmt) error {
+	header := []Expr{&LiteralExpr{Value: stmt.Name.Lexeme}}
+	if stmt.SuperClass != nil {
+		header = append(header, stmt.SuperClass)
+	}
+	p.buf.WriteString("(")
+	p.parenthesis("class", header...)
+	for _, method := range stmt.Methods {
+		method.Accept(p)
+	}
+	p.buf.WriteString(")")
+	return nil
+}
+
+func (p *Printer) VisitIfStmt(stmt *IfStmt) error {
+	p.buf.WriteString("(")
+	p.parenthesis("if", stmt.Condition)
+	stmt.ThenBranch.Accept(p)
+	if stmt.ElseBranch != nil {
+		p.buf.WriteString("(else ")
+		stmt.ElseBranch.Accept(p)
+		p.buf.WriteString(")")
+	}
+	p.buf.WriteString(")")
+	return nil
+}
+
+// labelOperands returns the operands that print an optional loop label
+func labelOperands(label *token.Token) []Expr {
+	if label == nil {
+		return nil
+	}
+	return []Expr{&LiteralExpr{Value: label.Lexeme}}
+}
+
+func (p *Printer) VisitWhileStmt(stmt *WhileStmt) error {
+	if stmt.Label != nil {
+		p.buf.WriteString(fmt.Sprintf("(label %q ", stmt.Label.Lexeme))
+		defer p.buf.WriteString(")")
+	}
+	p.buf.WriteString("(")
+	p.parenthesis("while", stmt.Condition)
+	stmt.Body.Accept(p)
+	if stmt.Increment != nil {
+		p.parenthesis("increment", stmt.Increment)
+	}
+	p.buf.WriteString(")")
+	return nil
+}
+
+func (p *Printer) VisitForInStmt(stmt *ForInStmt) error {
+	if stmt.Label != nil {
+		p.buf.WriteString(fmt.Sprintf("(label %q ", stmt.Label.Lexeme))
+		defer p.buf.WriteString(")")
+	}
+	p.buf.WriteString("(")
+	p.parenthesis("for-in", &LiteralExpr{Value: stmt.Name.Lexeme}, stmt.Iterable)
+	stmt.Body.Accept(p)
+	p.buf.WriteString(")")
+	return nil
+}
+
+func (p *Printer) VisitReturnStmt(stmt *ReturnStmt) error {
+	if stmt.Value == nil {
+		p.parenthesis("return")
+		return nil
+	}
+	p.parenthesis("return", stmt.Value)
+	return nil
+}
+
+func (p *Printer) VisitBreakStmt(stmt *BreakStmt) error {
+	p.parenthesis("break", labelOperands(stmt.Label)...)
+	return nil
+}
+
+func (p *Printer) VisitContinueStmt(stmt *ContinueStmt) error {
+	p.parenthesis("continue", labelOperands(stmt.Label)...)
+	return nil
+}
+
+func (p *Printer) VisitBlockStmt(stmt *BlockStmt) error {
+	for _, s := range stmt.Stmts {
+		s.Accept(p)
+	}
+	return nil
+}
+
+func (p *Printer) VisitBinaryExpr(expr *BinaryExpr) (interface{}, error) {
+	p.parenthesis(expr.Operator.Lexeme, expr.Left, expr.Right)
+	return nil, nil
+}
+
+func (p *Printer) VisitUnaryExpr(expr *UnaryExpr) (interface{}, error) {
+	p.parenthesis(expr.Operator.Lexeme, expr.Right)
+	return nil, nil
+}
+
+func (p *Printer) VisitLogicalExpr(expr *LogicExpr) (interface{}, error) {
+	p.parenthesis(expr.Operator.Lexeme, expr.Left, expr.Right)
+	return nil, nil
+}
+
+func (p *Printer) VisitAssignExpr(expr *AssignExpr) (interface{}, error) {
+	p.parenthesis("let", &LiteralExpr{Value: expr.Name.Lexeme}, expr.Value)
+	return nil, nil
+}
+
+func (p *Printer) VisitCallExpr(expr *CallExpr) (interface{}, error) {
+	p.parenthesis("call", append([]Expr{expr.Callee}, expr.Arguments...)...)
+	return nil, nil
+}
+
+func (p *Printer) VisitGetPropertyExpr(expr *GetPropertyExpr) (interface{}, error) {
+	p.parenthesis("get-prop", expr.Object, &LiteralExpr{Value: expr.Property.Lexeme})
+	return nil, nil
+}
+
+func (p *Printer) VisitSetPropertyExpr(expr *SetPropertyExpr) (interface{}, error) {
+	p.parenthesis("set-prop", expr.Object, &LiteralExpr{Value: expr.Property.Lexeme}, expr.Value)
+	return nil, nil
+}
+
+func (p *Printer) VisitGroupingExpr(expr *GroupingExpr) (interface{}, error) {
+	p.parenthesis("Group", expr.Child)
+	return nil, nil
+}
+
+func (p *Printer) VisitLiteralExpr(expr *LiteralExpr) (interface{}, error) {
+	switch expr.Value.(type) {
+	case string:
+		p.buf.WriteString(fmt.Sprintf("\"%s\"", expr.Value.(string)))
+	case float64:
+		p.buf.WriteString(strconv.FormatFloat(expr.Value.(float64), 'f', -1, 64))
+	case int:
+		p.buf.WriteString(strconv.Itoa(expr.Value.(int)))
+	case int64:
+		p.buf.WriteString(strconv.FormatInt(expr.Value.(int64), 10))
+	case *big.Int:
+		p.buf.WriteString(expr.Value.(*big.Int).String())
+	case *big.Rat:
+		p.buf.WriteString(token.FormatDecimal(expr.Value.(*big.Rat)) + "d")
+	case bool:
+		p.buf.WriteString(strconv.FormatBool(expr.Value.(bool)))
+	default:
+		reflVal := reflect.ValueOf(expr.Value)
+		if !reflVal.IsValid() {
+			p.buf.WriteString("nil")
+		}
+	}
+	return nil, nil
+}
+
+func (p *Printer) VisitVariableExpr(expr *VariableExpr) (interface{}, error) {
+	p.buf.WriteString(expr.Name.Lexeme)
+	return nil, nil
+}
+
+func (p *Printer) VisitThisExpr(expr *ThisExpr) (interface{}, error) {
+	p.buf.WriteString("this")
+	return nil, nil
+}
+
+func (p *Printer) VisitSuperExpr(expr *SuperExpr) (interface{}, error) {
+	p.parenthesis("super", &LiteralExpr{Value: expr.Property.Lexeme})
+	return nil, nil
+}
+
+func (p *Printer) VisitListExpr(expr *ListExpr) (interface{}, error) {
+	p.parenthesis("list", expr.Elements...)
+	return nil, nil
+}
+
+func (p *Printer) VisitMapExpr(expr *MapExpr) (interface{}, error) {
+	var entries []Expr
+	for idx := range expr.Keys {
+		entries = append(entries, expr.Keys[idx], expr.Values[idx])
+	}
+	p.parenthesis("map", entries...)
+	return nil, nil
+}
+
+func (p *Printer) VisitIndexGetExpr(expr *IndexGetExpr) (interface{}, error) {
+	p.parenthesis("index", expr.Object, expr.Index)
+	return nil, nil
+}
+
+func (p *Printer) VisitIndexSetExpr(expr *IndexSetExpr) (interface{}, error) {
+	p.parenthesis("set-index", expr.Object, expr.Index, expr.Value)
+	return nil, nil
+}
+
+func (p *Printer) VisitSliceExpr(expr *SliceExpr) (interface{}, error) {
+	bounds := []Expr{expr.Start, expr.End}
+	for idx, bound := range bounds {
+		if bound == nil {
+			bounds[idx] = &LiteralExpr{Value: nil}
+		}
+	}
+	p.parenthesis("slice", expr.Object, bounds[0], bounds[1])
+	return nil, nil
+}
+
+func (p *Printer) VisitUpdateExpr(expr *UpdateExpr) (interface{}, error) {
+	switch {
+	case expr.Operator.Lexeme != "++" && expr.Operator.Lexeme != "--":
+		p.parenthesis(expr.Operator.Lexeme, expr.Target, expr.Value)
+	case expr.Postfix:
+		p.parenthesis("post"+expr.Operator.Lexeme, expr.Target)
+	default:
+		p.parenthesis("pre"+expr.Operator.Lexeme, expr.Target)
+	}
+	return nil, nil
+}
+
+func (p *Printer) VisitInterpolationExpr(expr *InterpolationExpr) (interface{}, error) {
+	p.parenthesis("interpolate", expr.Parts...)
+	return nil, nil
+}
@@ -1,6 +1,7 @@
-package main
+package ast
 
 import (
+	"github.com/StanleyGY/Lox/gLox/token"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,8 +9,8 @@ import (
 
 func TestExprPrinter(t *testing.T) {
 	expr := &BinaryExpr{
-		Operator: &Token{
-			Type:   Plus,
+		Operator: &token.Token{
+			Type:   token.Plus,
 			Lexeme: "+",
 		},
 		Left: &LiteralExpr{
@@ -19,7 +20,7 @@ func TestExprPrinter(t *testing.T) {
 			Value: 2,
 		},
 	}
-	printer := &AstPrinter{}
+	printer := &Printer{}
 	res := printer.PrettyPrintExpr(expr)
 	assert.Equal(t, "(+ 1 2)", res)
 }
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/ast"
+	"github.com/StanleyGY/Lox/gLox/interp"
+	"github.com/StanleyGY/Lox/gLox/parser"
+	"github.com/StanleyGY/Lox/gLox/resolver"
+	"github.com/StanleyGY/Lox/gLox/scanner"
+	"io"
+	"os"
+)
+
+// Exit codes follow the spirit of sysexits.h
+const (
+	ExitOK    = 0
+	ExitUsage = 64
+	// Lexical, syntax and semantic errors in the program
+	ExitDataError    = 65
+	ExitRuntimeError = 70
+	ExitIOError      = 74
+)
+
+const usage = `usage: glox [flags] [script | -] [args...]
+
+Runs a Lox script, the source given by -e, or the standard input when script is "-".
+Starts an interactive session when neither a script nor -e is given.
+The trailing args are visible to the program as a list, through args().
+
+Flags:
+`
+
+type options struct {
+	dumpTokens bool
+	dumpAst    bool
+	checkOnly  bool
+	env        bool
+	filename   string
+	scriptArgs []string
+}
+
+func main() {
+	var opts options
+	var inline string
+
+	flags := flag.NewFlagSet("glox", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	flags.BoolVar(&opts.dumpTokens, "tokens", false, "print the scanned tokens")
+	flags.BoolVar(&opts.dumpAst, "ast", false, "print the syntax tree")
+	flags.BoolVar(&opts.checkOnly, "check", false, "stop after semantic analysis, without running the program")
+	flags.BoolVar(&opts.env, "env", false, "expose the environment variables through env(name)")
+	flags.StringVar(&inline, "e", "", "run `code` instead of a script")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(ExitOK)
+		}
+		os.Exit(ExitUsage)
+	}
+
+	var code string
+	switch {
+	case inline != "":
+		code = inline
+		opts.filename = "<inline>"
+		opts.scriptArgs = flags.Args()
+	case flags.NArg() == 0:
+		os.Exit(runRepl(opts))
+	default:
+		buf, err := readSource(flags.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(ExitIOError)
+		}
+		code = string(buf)
+		opts.filename = flags.Arg(0)
+		opts.scriptArgs = flags.Args()[1:]
+	}
+	os.Exit(run(code, opts, os.Stdout, os.Stderr))
+}
+
+func readSource(filename string) ([]byte, error) {
+	if filename == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(filename)
+}
+
+// run executes `code` and returns the exit code of the process
+func run(code string, opts options, stdout io.Writer, stderr io.Writer) int {
+	// Tokenize
+	s := &scanner.ScannerImpl{}
+	tokens, err := s.Scan(code)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitDataError
+	}
+	if opts.dumpTokens {
+		for _, t := range tokens {
+			fmt.Fprintf(stdout, "[%s] %s\n", t.Span.Start, t.ToString())
+		}
+	}
+
+	// Build ast tree
+	p := &parser.RDParser{}
+	stmts, err := p.Parse(tokens)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitDataError
+	}
+	if opts.dumpAst {
+		printer := &ast.Printer{}
+		for _, stmt := range stmts {
+			fmt.Fprintln(stdout, printer.PrettyPrintStmt(stmt))
+		}
+	}
+
+	// Run resolver
+	interpreter := interp.MakeInterpreter(
+		interp.WithStdout(stdout),
+		interp.WithStderr(stderr),
+		interp.WithFilename(opts.filename),
+	)
+	interpreter.DefineScriptArgs(opts.scriptArgs)
+	if opts.env {
+		interpreter.DefineEnv()
+	}
+	r := resolver.MakeResolver(interpreter)
+	if err = r.Resolve(stmts); err != nil {
+		fmt.Fprintln(stderr, err)
+		return ExitDataError
+	}
+	if opts.checkOnly {
+		return ExitOK
+	}
+
+	// Run interpreter
+	if err = interpreter.Evaluate(stmts); err != nil {
+		if exit, ok := err.(*interp.RuntimeExit); ok {
+			return exit.Code
+		}
+		fmt.Fprintln(stderr, err)
+		return ExitRuntimeError
+	}
+	return ExitOK
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("Test exit codes", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		assert.Equal(t, ExitOK, run("var a = 1;", options{}, &stdout, &stderr))
+		assert.Equal(t, ExitDataError, run("var a = \"unterminated;", options{}, &stdout, &stderr))
+		assert.Equal(t, ExitDataError, run("var a = ;", options{}, &stdout, &stderr))
+		assert.Equal(t, ExitDataError, run("print b;", options{}, &stdout, &stderr))
+		assert.Equal(t, ExitRuntimeError, run("print -\"a\";", options{}, &stdout, &stderr))
+	})
+
+	t.Run("Test program output", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		code := run("print 1 + 2;", options{}, &stdout, &stderr)
+		assert.Equal(t, ExitOK, code)
+		assert.Equal(t, "3\n", stdout.String())
+	})
+
+	t.Run("Test dumping ast", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		code := run("1 + 2;", options{dumpAst: true, checkOnly: true}, &stdout, &stderr)
+		assert.Equal(t, ExitOK, code)
+		assert.Equal(t, "(+ 1 2)\n", stdout.String())
+
+		stdout.Reset()
+		src := "fun f(a, b) { if (a) return; else return b; }\n" +
+			"class A { m() { return 1; } }\n" +
+			"class B < A { m() { return super.m(); } }"
+		code = run(src, options{dumpAst: true, checkOnly: true}, &stdout, &stderr)
+		assert.Equal(t, ExitOK, code)
+		assert.Equal(t, `((fun "f" "a" "b")((if a)(return)(else (return b))))`+"\n"+
+			`((class "A")((fun "m")(return 1)))`+"\n"+
+			`((class "B" A)((fun "m")(return (call (super "m")))))`+"\n", stdout.String())
+
+		// The tree is printed before the semantic analysis rejects it
+		stdout.Reset()
+		run("return;", options{dumpAst: true}, &stdout, &stderr)
+		assert.Equal(t, "(return)\n", stdout.String())
+	})
+
+	t.Run("Test dumping tokens", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		code := run("nil;", options{dumpTokens: true, checkOnly: true}, &stdout, &stderr)
+		assert.Equal(t, ExitOK, code)
+		expected := fmt.Sprintf("[1:1] %d nil\n[1:4] %d ;\n[1:5] %d \n", token.Nil, token.SemiColon, token.EOF)
+		assert.Equal(t, expected, stdout.String())
+	})
+
+	t.Run("Test script args and environment", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+
+		t.Setenv("GLOX_TEST", "yes")
+		code := run(`print args(); print env("GLOX_TEST");`, options{scriptArgs: []string{"a", "b"}, env: true}, &stdout, &stderr)
+		assert.Equal(t, ExitOK, code)
+		assert.Equal(t, "[\"a\", \"b\"]\nyes\n", stdout.String())
+
+		// The environment is only visible with -env
+		stderr.Reset()
+		code = run(`env("GLOX_TEST");`, options{}, &stdout, &stderr)
+		assert.Equal(t, ExitDataError, code)
+		assert.Contains(t, stderr.String(), "undefined variable: env")
+	})
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/ast"
+	"github.com/StanleyGY/Lox/gLox/interp"
+	"github.com/StanleyGY/Lox/gLox/parser"
+	"github.com/StanleyGY/Lox/gLox/resolver"
+	"github.com/StanleyGY/Lox/gLox/scanner"
+	"github.com/StanleyGY/Lox/gLox/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	replPrompt         = "> "
+	replContinuePrompt = "... "
+	replHistoryFile    = ".glox_history"
+)
+
+// Repl reads Lox source line by line and evaluates it on a single
+// interpreter, so declarations made on earlier lines stay visible.
+type Repl struct {
+	// Shared with "input()", so that neither reads ahead of the other
+	in  *bufio.Reader
+	out io.Writer
+
+	interpreter *interp.Interpreter
+	resolver    *resolver.Resolver
+
+	history     []string
+	historyPath string
+}
+
+// MakeRepl creates a session that reads entries from `in`, echoes values to `out`
+// and reports errors to `errOut`
+func MakeRepl(in io.Reader, out io.Writer, errOut io.Writer) *Repl {
+	reader := bufio.NewReader(in)
+	interpreter := interp.MakeInterpreter(interp.WithStdout(out), interp.WithStderr(errOut), interp.WithStdin(reader))
+	interpreter.DefineScriptArgs(nil)
+	return &Repl{
+		in:          reader,
+		out:         out,
+		interpreter: interpreter,
+		resolver:    resolver.MakeResolver(interpreter),
+	}
+}
+
+// DefineEnv exposes the environment variables through env(name).
+// It must be called before the session starts.
+func (r *Repl) DefineEnv() {
+	r.interpreter.DefineEnv()
+	r.resolver = resolver.MakeResolver(r.interpreter)
+}
+
+// LoadHistory reads previous lines from `path`. Lines entered in this
+// session are appended to the same file.
+func (r *Repl) LoadHistory(path string) {
+	r.historyPath = path
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(buf), "\n") {
+		if line != "" {
+			r.history = append(r.history, line)
+		}
+	}
+}
+
+func (r *Repl) addHistory(entry string) {
+	r.history = append(r.history, entry)
+	if r.historyPath == "" {
+		return
+	}
+	f, err := os.OpenFile(r.historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, entry)
+}
+
+func (r *Repl) printHistory() {
+	for idx, entry := range r.history {
+		fmt.Fprintf(r.out, "%5d  %s\n", idx+1, entry)
+	}
+}
+
+// readEntry reads lines until brackets are balanced and strings and comments are closed,
+// so that a block, a function body or a list can span multiple lines
+func (r *Repl) readEntry() (string, bool) {
+	var buf strings.Builder
+
+	fmt.Fprint(r.out, replPrompt)
+	for {
+		line, err := r.in.ReadString('\n')
+		if line == "" && err != nil {
+			break
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if strings.TrimSpace(line) != "" && line != ":history" {
+			r.addHistory(line)
+		}
+		buf.WriteString(line)
+		buf.WriteString("\n")
+
+		if !isIncomplete(buf.String()) {
+			return strings.TrimSpace(buf.String()), true
+		}
+		fmt.Fprint(r.out, replContinuePrompt)
+	}
+	// Input is closed. Evaluate what is left in the buffer, if any
+	return strings.TrimSpace(buf.String()), buf.Len() > 0
+}
+
+func isIncomplete(src string) bool {
+	s := &scanner.ScannerImpl{}
+	tokens, err := s.Scan(src)
+	if errs, ok := err.(scanner.ScanningErrors); ok {
+		for _, e := range errs {
+			if e.Unterminated {
+				return true
+			}
+		}
+		// Let the evaluation report the lexical error
+		return false
+	}
+	depth := 0
+	for _, t := range tokens {
+		switch t.Type {
+		case token.LeftBrace, token.LeftParen, token.LeftBracket:
+			depth++
+		case token.RightBrace, token.RightParen, token.RightBracket:
+			depth--
+		}
+	}
+	return depth > 0
+}
+
+// Run evaluates entries until the input is closed or "exit()" is called,
+// and returns the exit code of the session
+func (r *Repl) Run() int {
+	for {
+		entry, ok := r.readEntry()
+		if !ok {
+			fmt.Fprintln(r.out)
+			return ExitOK
+		}
+		if entry == "" {
+			continue
+		}
+		if entry == ":history" {
+			r.printHistory()
+			continue
+		}
+
+		if err := r.eval(entry); err != nil {
+			if exit, ok := err.(*interp.RuntimeExit); ok {
+				return exit.Code
+			}
+			fmt.Fprintln(r.interpreter.Stderr(), err)
+		}
+	}
+}
+
+func (r *Repl) eval(src string) error {
+	s := &scanner.ScannerImpl{}
+	tokens, err := s.Scan(src)
+	if err != nil {
+		return err
+	}
+
+	p := &parser.RDParser{}
+	stmts, err := p.Parse(tokens)
+	if err != nil {
+		return err
+	}
+
+	if err = r.resolver.Resolve(stmts); err != nil {
+		return err
+	}
+	// Declarations skipped by a runtime error can be made again in a later entry
+	defer r.resolver.SyncGlobals()
+
+	for _, stmt := range stmts {
+		// Echo the value of a bare expression, e.g. "1 + 2"
+		if exprStmt, ok := stmt.(*ast.InlineExprStmt); ok {
+			val, err := r.interpreter.EvaluateExpr(exprStmt.Child)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(r.out, interp.Stringify(val))
+			continue
+		}
+		if err = r.interpreter.Evaluate([]ast.Stmt{stmt}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runRepl(opts options) int {
+	repl := MakeRepl(os.Stdin, os.Stdout, os.Stderr)
+	if opts.env {
+		repl.DefineEnv()
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		repl.LoadHistory(filepath.Join(home, replHistoryFile))
+	}
+	return repl.Run()
+}
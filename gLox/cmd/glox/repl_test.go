@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepl(t *testing.T) {
+	t.Run("Test echoing bare expressions", func(t *testing.T) {
+		var out bytes.Buffer
+		repl := MakeRepl(strings.NewReader("var a = 1;\na + 2;\n"), &out, &out)
+		repl.Run()
+		assert.Equal(t, "> > 3\n> \n", out.String())
+	})
+
+	t.Run("Test multi-line input", func(t *testing.T) {
+		var out bytes.Buffer
+		repl := MakeRepl(strings.NewReader("fun f(x) {\n  return x * 10;\n}\nf(2);\n"), &out, &out)
+		repl.Run()
+		assert.Equal(t, "> ... ... > 20\n> \n", out.String())
+	})
+
+	t.Run("Test errors do not end the session", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		repl := MakeRepl(strings.NewReader("undefinedvar;\n{ var b = 1;\nvar b = 2; }\nvar c = 5;\nc;\n"), &out, &errOut)
+		repl.Run()
+		assert.Contains(t, errOut.String(), "undefined variable: undefinedvar")
+		assert.Contains(t, errOut.String(), "redefining variable: b")
+		assert.Equal(t, "> > ... > > 5\n> \n", out.String())
+	})
+
+	t.Run("Test failed entries can be entered again", func(t *testing.T) {
+		for _, src := range []string{
+			// Resolution error in an initializer
+			"var q = zz;\nvar q = 1;\nq;\n",
+			// Runtime error in an initializer
+			"var q = 1 / 0;\nvar q = 1;\nq;\n",
+			// Resolution error in a function body
+			"fun q() { return zz; }\nfun q() { return 1; }\nq();\n",
+		} {
+			var out, errOut bytes.Buffer
+			repl := MakeRepl(strings.NewReader(src), &out, &errOut)
+			repl.Run()
+			assert.NotContains(t, errOut.String(), "redefining", src)
+			assert.True(t, strings.HasSuffix(out.String(), "> > 1\n> \n"), src)
+		}
+	})
+
+	t.Run("Test echo uses the display rules of print", func(t *testing.T) {
+		var out bytes.Buffer
+		repl := MakeRepl(strings.NewReader("nil;\n0.1d;\n\"a\";\nvar x;\nx;\n"), &out, &out)
+		repl.Run()
+		assert.Equal(t, "> nil\n> 0.1\n> a\n> > nil\n> \n", out.String())
+	})
+
+	t.Run("Test exit code", func(t *testing.T) {
+		var out bytes.Buffer
+		repl := MakeRepl(strings.NewReader("exit(3);\nprint 1;\n"), &out, &out)
+		assert.Equal(t, 3, repl.Run())
+		assert.Equal(t, "> ", out.String())
+
+		repl = MakeRepl(strings.NewReader("print 1;\n"), &out, &out)
+		assert.Equal(t, ExitOK, repl.Run())
+	})
+
+	t.Run("Test input() reads the lines after the entry", func(t *testing.T) {
+		var out bytes.Buffer
+		repl := MakeRepl(strings.NewReader("var x = input();\nhello\nprint x;\n"), &out, &out)
+		repl.Run()
+		assert.Equal(t, "> > hello\n> \n", out.String())
+	})
+
+	t.Run("Test multi-line lists, strings, interpolations and comments", func(t *testing.T) {
+		for src, echo := range map[string]string{
+			"var xs = [\n1,\n2];\nxs;\n":      "[1, 2]",
+			"var s = \"\"\"a\nb\"\"\";\ns;\n": "a\nb",
+			"var n = 1;\n\"${\nn + 1}\";\n":   "2",
+			"/* a\nb */ 3;\n":                 "3",
+		} {
+			var out bytes.Buffer
+			repl := MakeRepl(strings.NewReader(src), &out, &out)
+			repl.Run()
+			assert.Contains(t, out.String(), "... ", src)
+			assert.True(t, strings.HasSuffix(out.String(), " "+echo+"\n> \n"), out.String())
+		}
+	})
+}
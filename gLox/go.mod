@@ -1,4 +1,4 @@
-module lox
+module github.com/StanleyGY/Lox/gLox
 
 go 1.22.0
 
@@ -1,9 +1,23 @@
-package main
+package interp
 
-import "fmt"
+import (
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/ast"
+)
 
+// Value is a runtime value of a Lox program: nil, bool, int64, float64, string,
+// a LoxCallable, a *LoxClassInstance, a *LoxList or a *LoxMap
+type Value = interface{}
+
+// builtinObject is a built-in value with native methods, like a list or a map
+type builtinObject interface {
+	FindMethod(name string) (*NativeFunction, bool)
+}
+
+// LoxCallable is implemented by every value that can be called.
+// `args` are evaluated by the caller, and their count is validated against Arity().
 type LoxCallable interface {
-	Call(interpreter *Interpreter, args []Expr) (interface{}, error)
+	Call(interpreter *Interpreter, args []Value) (Value, error)
 	// Closure() *Environment
 	Arity() int
 }
@@ -11,10 +25,10 @@ type LoxCallable interface {
 type LoxFunction struct {
 	IsInitializer bool
 	Closure       *Environment
-	Declaration   *FuncDeclStmt
+	Declaration   *ast.FuncDeclStmt
 }
 
-func (f *LoxFunction) Call(interpreter *Interpreter, args []Expr) (interface{}, error) {
+func (f *LoxFunction) Call(interpreter *Interpreter, args []Value) (Value, error) {
 	var err error
 
 	// Create a new env for the function call
@@ -24,14 +38,8 @@ func (f *LoxFunction) Call(interpreter *Interpreter, args []Expr) (interface{},
 	}
 
 	// Copy arguments into current env
-	for idx := range args {
-		var param string
-		var argv interface{}
-		if argv, err = args[idx].Accept(interpreter); err != nil {
-			return nil, err
-		}
-		param = f.Declaration.Params[idx].Lexeme
-		env.CreateBinding(param, argv, true)
+	for idx, argv := range args {
+		env.CreateBinding(f.Declaration.Params[idx].Lexeme, argv, true)
 	}
 
 	// Evaluate function body
@@ -40,7 +48,11 @@ func (f *LoxFunction) Call(interpreter *Interpreter, args []Expr) (interface{},
 
 	lastEnv := interpreter.CurrEnv
 	interpreter.CurrEnv = env
-	defer func() { interpreter.CurrEnv = lastEnv }()
+	interpreter.pushFrame(f.Declaration.Name.Lexeme)
+	defer func() {
+		interpreter.CurrEnv = lastEnv
+		interpreter.popFrame()
+	}()
 
 	if err = f.Declaration.Body.Accept(interpreter); err != nil {
 		if returnVal, hasReturn = err.(*RuntimeReturn); !hasReturn {
@@ -76,7 +88,7 @@ func (c *LoxClass) FindMethod(name string) (*LoxFunction, bool) {
 	return val, ok
 }
 
-func (c *LoxClass) Call(interpreter *Interpreter, args []Expr) (interface{}, error) {
+func (c *LoxClass) Call(interpreter *Interpreter, args []Value) (Value, error) {
 	// Create axn instance of the class
 	properties := make(map[string]interface{})
 
@@ -88,7 +100,9 @@ func (c *LoxClass) Call(interpreter *Interpreter, args []Expr) (interface{}, err
 	// Immediately call the user-defined constructor
 	if c.Initializer != nil {
 		c.Initializer.Closure.CreateBinding("this", instance, true)
-		c.Initializer.Call(interpreter, args)
+		if _, err := c.Initializer.Call(interpreter, args); err != nil {
+			return nil, err
+		}
 	}
 	return instance, nil
 }
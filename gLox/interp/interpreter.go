@@ -0,0 +1,965 @@
+package interp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/ast"
+	"github.com/StanleyGY/Lox/gLox/token"
+	"io"
+	"math/big"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// StackFrame is an active function call when a runtime error happens
+type StackFrame struct {
+	// Name of the function, or empty for the top-level script
+	Function string
+	File     string
+	Line     int
+}
+
+func (f StackFrame) String() string {
+	location := fmt.Sprintf("line %d", f.Line)
+	if f.File != "" {
+		location = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	if f.Function == "" {
+		return fmt.Sprintf("[%s] in script", location)
+	}
+	return fmt.Sprintf("[%s] in %s()", location, f.Function)
+}
+
+func formatStackTrace(buf *bytes.Buffer, trace []StackFrame) {
+	for _, frame := range trace {
+		buf.WriteString("\n")
+		buf.WriteString(frame.String())
+	}
+}
+
+type RuntimeError struct {
+	Reason string
+	// Span of the node that fails
+	Span token.Span
+	// Active calls from the innermost to the outermost
+	Trace []StackFrame
+}
+
+func (e RuntimeError) Error() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("runtime error: %s", e.Reason))
+	formatStackTrace(&buf, e.Trace)
+	return buf.String()
+}
+
+type RuntimeTypeError struct {
+	Operator *token.Token
+	Vals     []interface{}
+	Trace    []StackFrame
+}
+
+func (e RuntimeTypeError) Error() string {
+	var buf bytes.Buffer
+	buf.WriteString("runtime error: invalid types for operator ")
+	buf.WriteString(e.Operator.Lexeme)
+	buf.WriteString(" on values: ")
+	for i, v := range e.Vals {
+		buf.WriteString(fmt.Sprintf("%s (%s)", quote(v), typeName(v)))
+		if i < len(e.Vals)-1 {
+			buf.WriteString(", ")
+		}
+	}
+	formatStackTrace(&buf, e.Trace)
+	return buf.String()
+}
+
+type RuntimeReturn struct {
+	Value interface{}
+}
+
+func (e RuntimeReturn) Error() string {
+	return "runtime error: return"
+}
+
+// RuntimeBreak exits the innermost loop, or the loop named `Label` when it's not empty
+type RuntimeBreak struct {
+	Label string
+}
+
+func (e RuntimeBreak) Error() string {
+	return "runtime error: break"
+}
+
+// RuntimeContinue ends the iteration of the innermost loop, or of the loop named `Label` when it's not empty
+type RuntimeContinue struct {
+	Label string
+}
+
+func (e RuntimeContinue) Error() string {
+	return "runtime error: continue"
+}
+
+// targets tells whether a "break" or a "continue" to `label` applies to a loop with `loopLabel`
+func targets(label string, loopLabel *token.Token) bool {
+	return label == "" || (loopLabel != nil && loopLabel.Lexeme == label)
+}
+
+// jumpLabel returns the label of a "break" or a "continue", or an empty string
+func jumpLabel(label *token.Token) string {
+	if label == nil {
+		return ""
+	}
+	return label.Lexeme
+}
+
+// RuntimeExit is raised by the "exit()" native to stop the program
+type RuntimeExit struct {
+	Code int
+}
+
+func (e RuntimeExit) Error() string {
+	return fmt.Sprintf("exit with code %d", e.Code)
+}
+
+type Environment struct {
+	ParentEnv *Environment
+	Bindings  map[string]interface{}
+}
+
+// CreateBinding creates a new binding in the current scope
+func (e *Environment) CreateBinding(name string, val interface{}, update bool) bool {
+	if _, ok := e.Bindings[name]; ok && !update {
+		return false
+	}
+	e.Bindings[name] = val
+	return true
+}
+
+func (e *Environment) FindBinding(name string, dist int) (interface{}, bool) {
+	ancestorEnv := e
+	for i := 0; i < dist; i++ {
+		ancestorEnv = ancestorEnv.ParentEnv
+	}
+	val, ok := ancestorEnv.Bindings[name]
+	return val, ok
+}
+
+func (e *Environment) UpdateBinding(name string, val interface{}, dist int) bool {
+	ancestorEnv := e
+	for i := 0; i < dist; i++ {
+		ancestorEnv = ancestorEnv.ParentEnv
+	}
+	_, ok := ancestorEnv.Bindings[name]
+	if !ok {
+		return false
+	}
+
+	ancestorEnv.Bindings[name] = val
+	return true
+}
+
+type Interpreter struct {
+	// Number of scope hops between a variable usage and its declaration
+	ScopeHops map[ast.Expr]int
+	// In Lox, runtime environment is a dynamic manifestation of static scope
+	Globals *Environment
+	CurrEnv *Environment
+
+	stdout io.Writer
+	stderr io.Writer
+	stdin  *bufio.Reader
+
+	// Name of the script file, shown in stack traces
+	filename string
+	// Active calls of Lox functions
+	frames []callFrame
+	// Location of the call expression being evaluated
+	callSite token.Span
+}
+
+type callFrame struct {
+	function string
+	// Where the function is called from
+	callSite token.Span
+}
+
+// Option customizes an interpreter created by MakeInterpreter
+type Option func(*Interpreter)
+
+// WithStdout sets where "print" and the output natives write to
+func WithStdout(w io.Writer) Option {
+	return func(p *Interpreter) {
+		p.stdout = w
+	}
+}
+
+// WithStderr sets where error reports and other diagnostics are written to
+func WithStderr(w io.Writer) Option {
+	return func(p *Interpreter) {
+		p.stderr = w
+	}
+}
+
+// WithStdin sets where "input()" and the input natives read from.
+// A *bufio.Reader is used as is, so that the caller can keep reading from it.
+func WithStdin(r io.Reader) Option {
+	return func(p *Interpreter) {
+		p.stdin = bufio.NewReader(r)
+	}
+}
+
+// WithFilename sets the script file name shown in stack traces
+func WithFilename(name string) Option {
+	return func(p *Interpreter) {
+		p.filename = name
+	}
+}
+
+func MakeInterpreter(opts ...Option) *Interpreter {
+	globals := &Environment{
+		Bindings: make(map[string]interface{}),
+	}
+	interpreter := &Interpreter{
+		Globals:   globals,
+		CurrEnv:   globals,
+		ScopeHops: make(map[ast.Expr]int),
+		stdout:    os.Stdout,
+		stderr:    os.Stderr,
+	}
+	for _, opt := range opts {
+		opt(interpreter)
+	}
+	if interpreter.stdin == nil {
+		interpreter.stdin = bufio.NewReader(os.Stdin)
+	}
+	interpreter.definePrelude()
+	return interpreter
+}
+
+// Stdout is the writer that a native function should print to
+func (p *Interpreter) Stdout() io.Writer {
+	return p.stdout
+}
+
+// Stderr is the writer that error reports and other diagnostics should go to
+func (p *Interpreter) Stderr() io.Writer {
+	return p.stderr
+}
+
+// Stdin is the reader that a native function should read input from
+func (p *Interpreter) Stdin() *bufio.Reader {
+	return p.stdin
+}
+
+// GlobalNames lists the names that are bound in the global environment
+// before any Lox code runs, e.g. native functions
+func (p *Interpreter) GlobalNames() []string {
+	names := make([]string, 0, len(p.Globals.Bindings))
+	for name := range p.Globals.Bindings {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Resolve tracks where a referenced variable is declared.
+// This is possible since Lox uses static scope.
+func (p *Interpreter) Resolve(expr ast.Expr, dist int) {
+	p.ScopeHops[expr] = dist
+}
+
+func (p *Interpreter) Evaluate(stmts []ast.Stmt) error {
+	for _, stmt := range stmts {
+		if err := stmt.Accept(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Interpreter) EvaluateExpr(expr ast.Expr) (interface{}, error) {
+	return expr.Accept(p)
+}
+
+// declare creates a binding in the current scope.
+// A native function in the global scope can be replaced by a declaration of the program.
+func (p *Interpreter) declare(name string, val interface{}) bool {
+	if p.CurrEnv == p.Globals {
+		if existing, ok := p.Globals.Bindings[name]; ok {
+			if _, isNative := existing.(*NativeFunction); isNative {
+				return p.CurrEnv.CreateBinding(name, val, true)
+			}
+		}
+	}
+	return p.CurrEnv.CreateBinding(name, val, false)
+}
+
+// Stringify formats a value the way "print" displays it
+func Stringify(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case *big.Rat:
+		return token.FormatDecimal(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// quote is like Stringify, but makes strings distinguishable in error messages
+func quote(v interface{}) string {
+	if str, ok := v.(string); ok {
+		return strconv.Quote(str)
+	}
+	return Stringify(v)
+}
+
+// emitRuntimeError creates an error located at `node`, with a trace of the active calls
+func (p *Interpreter) emitRuntimeError(node ast.Node, reason string) error {
+	return &RuntimeError{Reason: reason, Span: node.Span(), Trace: p.stackTrace(node.Span())}
+}
+
+// stackTrace lists the active calls, given that the innermost call is executing `at`
+func (p *Interpreter) stackTrace(at token.Span) []StackFrame {
+	var trace []StackFrame
+
+	line := at.Start.Line
+	for i := len(p.frames) - 1; i >= 0; i-- {
+		trace = append(trace, StackFrame{Function: p.frames[i].function, File: p.filename, Line: line})
+		line = p.frames[i].callSite.Start.Line
+	}
+	return append(trace, StackFrame{File: p.filename, Line: line})
+}
+
+func (p *Interpreter) pushFrame(function string) {
+	p.frames = append(p.frames, callFrame{function: function, callSite: p.callSite})
+}
+
+func (p *Interpreter) popFrame() {
+	p.frames = p.frames[:len(p.frames)-1]
+}
+
+func (p *Interpreter) isTruthy(v interface{}) bool {
+	t := reflect.ValueOf(v)
+	if !t.IsValid() {
+		return false
+	}
+	if t.Kind() == reflect.Bool {
+		return v.(bool)
+	}
+	// Empty lists and maps are falsy
+	switch v := v.(type) {
+	case *LoxList:
+		return len(v.Elements) > 0
+	case *LoxMap:
+		return v.Len() > 0
+	}
+	return true
+}
+
+func (p *Interpreter) checkType(op *token.Token, v interface{}, expectedTypes []reflect.Kind) error {
+	t := reflect.ValueOf(v)
+	if slices.Contains(expectedTypes, t.Kind()) {
+		return nil
+	}
+	return RuntimeTypeError{Operator: op, Vals: []interface{}{v}, Trace: p.stackTrace(op.Span)}
+}
+
+func (p *Interpreter) checkTypes(op *token.Token, vals []interface{}, expectedTypes []reflect.Kind) error {
+	var err error
+
+	for _, et := range expectedTypes {
+		valid := true
+
+		// Make sure all `vals` have the same types
+		for _, v := range vals {
+			if err = p.checkType(op, v, []reflect.Kind{et}); err != nil {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			return nil
+		}
+	}
+	return RuntimeTypeError{Operator: op, Vals: vals, Trace: p.stackTrace(op.Span)}
+}
+
+func (p *Interpreter) VisitIfStmt(stmt *ast.IfStmt) error {
+	var r interface{}
+	var err error
+	if r, err = stmt.Condition.Accept(p); err != nil {
+		return err
+	}
+	if p.isTruthy(r) {
+		return stmt.ThenBranch.Accept(p)
+	}
+	if stmt.ElseBranch != nil {
+		return stmt.ElseBranch.Accept(p)
+	}
+	return nil
+}
+
+func (p *Interpreter) VisitWhileStmt(stmt *ast.WhileStmt) error {
+	var r interface{}
+	var err error
+	for {
+		if r, err = stmt.Condition.Accept(p); err != nil {
+			return err
+		}
+		if !p.isTruthy(r) {
+			return nil
+		}
+		if err = stmt.Body.Accept(p); err != nil {
+			if brk, ok := err.(*RuntimeBreak); ok && targets(brk.Label, stmt.Label) {
+				return nil
+			}
+			// Jumps to an outer loop keep unwinding
+			if cont, ok := err.(*RuntimeContinue); !ok || !targets(cont.Label, stmt.Label) {
+				return err
+			}
+		}
+		if stmt.Increment != nil {
+			if _, err = stmt.Increment.Accept(p); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *Interpreter) VisitForInStmt(stmt *ast.ForInStmt) error {
+	var iterable interface{}
+	var err error
+
+	if iterable, err = stmt.Iterable.Accept(p); err != nil {
+		return err
+	}
+	err = p.iterate(stmt, iterable, func(element Value) error {
+		// Each iteration binds the loop variable in a new environment,
+		// so that closures capture the element of their own iteration
+		env := &Environment{
+			ParentEnv: p.CurrEnv,
+			Bindings:  map[string]interface{}{stmt.Name.Lexeme: element},
+		}
+		p.CurrEnv = env
+		defer func() { p.CurrEnv = env.ParentEnv }()
+		if err := stmt.Body.Accept(p); err != nil {
+			if cont, ok := err.(*RuntimeContinue); !ok || !targets(cont.Label, stmt.Label) {
+				return err
+			}
+		}
+		return nil
+	})
+	if brk, ok := err.(*RuntimeBreak); ok && targets(brk.Label, stmt.Label) {
+		return nil
+	}
+	return err
+}
+
+func (p *Interpreter) VisitBlockStmt(stmt *ast.BlockStmt) error {
+	newEnv := &Environment{
+		ParentEnv: p.CurrEnv,
+		Bindings:  make(map[string]interface{}),
+	}
+	p.CurrEnv = newEnv
+	defer func() { p.CurrEnv = newEnv.ParentEnv }()
+
+	for _, c := range stmt.Stmts {
+		if err := c.Accept(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Interpreter) VisitInlineExprStmt(stmt *ast.InlineExprStmt) error {
+	_, err := stmt.Child.Accept(p)
+	return err
+}
+
+func (p *Interpreter) VisitPrintStmt(stmt *ast.PrintStmt) error {
+	var val interface{}
+	var err error
+
+	if val, err = stmt.Child.Accept(p); err != nil {
+		return err
+	}
+	fmt.Fprintln(p.stdout, Stringify(val))
+	return nil
+}
+
+func (p *Interpreter) VisitVarDeclStmt(stmt *ast.VarDeclStmt) error {
+	var val interface{}
+	var err error
+
+	// A variable without initializer is nil
+	if stmt.Initializer != nil {
+		if val, err = stmt.Initializer.Accept(p); err != nil {
+			return err
+		}
+	}
+	if !p.declare(stmt.Name.Lexeme, val) {
+		return p.emitRuntimeError(stmt, fmt.Sprintf("double declaration for variable: %s", stmt.Name.Lexeme))
+	}
+	return nil
+}
+
+func (p *Interpreter) VisitFunDeclStmt(stmt *ast.FuncDeclStmt) error {
+	// Things about closure:
+	// For a function, closure is the runtime environment when the function is declared
+	// For a method, closure is the runtime environment when the method is declared
+	//					and a "this" property when the class is instantiated
+	loxFunc := &LoxFunction{Declaration: stmt, Closure: p.CurrEnv}
+	if !p.declare(stmt.Name.Lexeme, loxFunc) {
+		return p.emitRuntimeError(stmt, fmt.Sprintf("double declaration for function: %s", stmt.Name.Lexeme))
+	}
+	return nil
+}
+
+func (p *Interpreter) VisitClassDeclStmt(stmt *ast.ClassDeclStmt) error {
+	var initializer *LoxFunction
+	var superClass *LoxClass
+
+	// Handle inheritance
+	if stmt.SuperClass != nil {
+		val, ok := p.CurrEnv.FindBinding(stmt.SuperClass.Name.Lexeme, p.ScopeHops[stmt.SuperClass])
+		if !ok {
+			return p.emitRuntimeError(stmt, fmt.Sprintf("super class is declared: %s", stmt.SuperClass.Name.Lexeme))
+		}
+		if superClass, ok = val.(*LoxClass); !ok {
+			return p.emitRuntimeError(stmt, fmt.Sprintf("super class is not a class: %s", stmt.SuperClass.Name.Lexeme))
+		}
+	}
+
+	// Handle class methods
+	methods := make(map[string]*LoxFunction)
+	for _, funcStmt := range stmt.Methods {
+		// The environment chain of a method when it's called looks like this:
+		// Global -> Block -> Class Closure ("super", "this") -> Method env.
+		// The class closure exists even without super class, as the resolver counts it.
+		// Unlike "this" which is specific to individual class instances,
+		// "super" is shared across all instances. So we only bind once, and bind
+		// when class is declared.
+		closure := &Environment{
+			ParentEnv: p.CurrEnv,
+			Bindings:  make(map[string]interface{}),
+		}
+		if stmt.SuperClass != nil {
+			closure.CreateBinding("super", superClass, true)
+		}
+
+		m := &LoxFunction{Declaration: funcStmt, Closure: closure, IsInitializer: false}
+		mName := funcStmt.Name.Lexeme
+		methods[mName] = m
+
+		if mName == "init" {
+			initializer = m
+			m.IsInitializer = true
+		}
+	}
+
+	// Try create a binding for the class decl
+	klass := &LoxClass{
+		Name:        stmt.Name.Lexeme,
+		SuperClass:  superClass,
+		Initializer: initializer,
+		Methods:     methods,
+	}
+	if !p.declare(stmt.Name.Lexeme, klass) {
+		return p.emitRuntimeError(stmt, fmt.Sprintf("double declaration for class: %s", stmt.Name.Lexeme))
+	}
+	return nil
+}
+
+func (p *Interpreter) VisitReturnStmt(stmt *ast.ReturnStmt) error {
+	var value interface{}
+	var err error
+
+	if stmt.Value != nil {
+		if value, err = stmt.Value.Accept(p); err != nil {
+			return err
+		}
+	}
+	// Return an error to unwind the call stack until reaching CallExpr
+	return &RuntimeReturn{Value: value}
+}
+
+func (p *Interpreter) VisitBreakStmt(stmt *ast.BreakStmt) error {
+	// Return an error to unwind the call stack until reaching WhileStmt
+	return &RuntimeBreak{Label: jumpLabel(stmt.Label)}
+}
+
+func (p *Interpreter) VisitContinueStmt(stmt *ast.ContinueStmt) error {
+	// Return an error to unwind the loop body until reaching WhileStmt or ForInStmt
+	return &RuntimeContinue{Label: jumpLabel(stmt.Label)}
+}
+
+func (p *Interpreter) VisitBinaryExpr(expr *ast.BinaryExpr) (interface{}, error) {
+	var leftVal interface{}
+	var rightVal interface{}
+	var err error
+
+	if leftVal, err = expr.Left.Accept(p); err != nil {
+		return nil, err
+	}
+	if rightVal, err = expr.Right.Accept(p); err != nil {
+		return nil, err
+	}
+	return p.binaryOperation(expr, leftVal, rightVal)
+}
+
+// binaryOperation applies the operator of `expr` on values that are already evaluated
+func (p *Interpreter) binaryOperation(expr *ast.BinaryExpr, leftVal interface{}, rightVal interface{}) (interface{}, error) {
+	var err error
+
+	switch expr.Operator.Type {
+	case token.Plus:
+		if p.checkTypes(expr.Operator, []interface{}{leftVal, rightVal}, []reflect.Kind{reflect.String}) == nil {
+			return strings.Join([]string{leftVal.(string), rightVal.(string)}, ""), nil
+		}
+		fallthrough
+	case token.Minus:
+		fallthrough
+	case token.Star:
+		fallthrough
+	case token.Slash:
+		fallthrough
+	case token.Percent:
+		fallthrough
+	case token.StarStar:
+		fallthrough
+	case token.Greater:
+		fallthrough
+	case token.GreaterEqual:
+		fallthrough
+	case token.Less:
+		fallthrough
+	case token.LessEqual:
+		if err = p.checkNumbers(expr.Operator, leftVal, rightVal); err != nil {
+			return nil, err
+		}
+		return p.arithmetic(expr, leftVal, rightVal)
+	case token.Ampersand, token.Pipe, token.Caret, token.LessLess, token.GreaterGreater:
+		if err = p.checkIntegers(expr.Operator, leftVal, rightVal); err != nil {
+			return nil, err
+		}
+		return p.bitwise(expr, leftVal, rightVal)
+	case token.BangEqual, token.EqualEqual:
+		if mixesFloatAndDecimal(leftVal, rightVal) {
+			break
+		}
+		if expr.Operator.Type == token.BangEqual {
+			return !isEqual(leftVal, rightVal), nil
+		}
+		return isEqual(leftVal, rightVal), nil
+	}
+
+	return nil, RuntimeTypeError{Operator: expr.Operator, Vals: []interface{}{leftVal, rightVal}, Trace: p.stackTrace(expr.Operator.Span)}
+}
+
+func (p *Interpreter) VisitLogicalExpr(expr *ast.LogicExpr) (interface{}, error) {
+	var leftVal interface{}
+	var err error
+
+	if leftVal, err = expr.Left.Accept(p); err != nil {
+		return nil, err
+	}
+
+	// Logical operator will return a value that guarantees
+	// the truthness of this operator
+	switch expr.Operator.Type {
+	case token.And:
+		if !p.isTruthy(leftVal) {
+			return false, nil
+		}
+		return expr.Right.Accept(p)
+	case token.Or:
+		if p.isTruthy(leftVal) {
+			return leftVal, nil
+		}
+		return expr.Right.Accept(p)
+	}
+
+	return nil, RuntimeTypeError{Operator: expr.Operator, Vals: []interface{}{leftVal}, Trace: p.stackTrace(expr.Operator.Span)}
+}
+
+func (p *Interpreter) VisitUnaryExpr(expr *ast.UnaryExpr) (interface{}, error) {
+	var rightVal interface{}
+	var err error
+
+	if rightVal, err = expr.Right.Accept(p); err != nil {
+		return nil, err
+	}
+
+	switch expr.Operator.Type {
+	case token.Minus:
+		if err = p.checkNumbers(expr.Operator, rightVal); err != nil {
+			return nil, err
+		}
+		return negate(rightVal), nil
+	case token.Tilde:
+		if err = p.checkIntegers(expr.Operator, rightVal); err != nil {
+			return nil, err
+		}
+		return complement(rightVal), nil
+	case token.Bang:
+		return !p.isTruthy(rightVal), nil
+	}
+
+	return nil, RuntimeTypeError{Operator: expr.Operator, Vals: []interface{}{rightVal}, Trace: p.stackTrace(expr.Operator.Span)}
+}
+
+func (p *Interpreter) VisitGroupingExpr(expr *ast.GroupingExpr) (interface{}, error) {
+	return expr.Child.Accept(p)
+}
+
+func (p *Interpreter) VisitAssignExpr(expr *ast.AssignExpr) (interface{}, error) {
+	var val interface{}
+	var err error
+
+	if val, err = expr.Value.Accept(p); err != nil {
+		return nil, err
+	}
+
+	// Design choice: the variable must be defined in the current scope
+	// before assigning another value
+	if !p.CurrEnv.UpdateBinding(expr.Name.Lexeme, val, p.ScopeHops[expr]) {
+		return nil, p.emitRuntimeError(expr, fmt.Sprintf("assigns value to an undefined variable: %s", expr.Name.Lexeme))
+	}
+	return val, nil
+}
+
+func (p *Interpreter) VisitUpdateExpr(expr *ast.UpdateExpr) (interface{}, error) {
+	var loxInstance *LoxClassInstance
+	var object interface{}
+	var index interface{}
+	var oldVal interface{}
+	var operand interface{}
+	var newVal interface{}
+	var err error
+
+	// Read the target, evaluating the object of a property or an element only once
+	switch target := expr.Target.(type) {
+	case *ast.VariableExpr:
+		if oldVal, err = target.Accept(p); err != nil {
+			return nil, err
+		}
+	case *ast.IndexGetExpr:
+		if object, err = target.Object.Accept(p); err != nil {
+			return nil, err
+		}
+		if index, err = target.Index.Accept(p); err != nil {
+			return nil, err
+		}
+		if oldVal, err = p.getIndex(target, object, index); err != nil {
+			return nil, err
+		}
+	case *ast.GetPropertyExpr:
+		var ok bool
+
+		if object, err = target.Object.Accept(p); err != nil {
+			return nil, err
+		}
+		if loxInstance, ok = object.(*LoxClassInstance); !ok {
+			return nil, p.emitRuntimeError(target, "cannot convert to a LoxClass instance")
+		}
+		if oldVal, ok = loxInstance.FindProperty(target.Property.Lexeme); !ok {
+			return nil, p.emitRuntimeError(target, fmt.Sprintf("class %s does not have the field %s", loxInstance.Class, target.Property.Lexeme))
+		}
+	}
+
+	if operand, err = expr.Value.Accept(p); err != nil {
+		return nil, err
+	}
+	binary := &ast.BinaryExpr{Operator: expr.Operator, Left: expr.Target, Right: expr.Value}
+	binary.SetSpan(expr.Span())
+	if newVal, err = p.binaryOperation(binary, oldVal, operand); err != nil {
+		return nil, err
+	}
+
+	// Write the target
+	switch target := expr.Target.(type) {
+	case *ast.VariableExpr:
+		if !p.CurrEnv.UpdateBinding(target.Name.Lexeme, newVal, p.ScopeHops[target]) {
+			return nil, p.emitRuntimeError(expr, fmt.Sprintf("assigns value to an undefined variable: %s", target.Name.Lexeme))
+		}
+	case *ast.IndexGetExpr:
+		if err = p.setIndex(target, object, index, newVal); err != nil {
+			return nil, err
+		}
+	case *ast.GetPropertyExpr:
+		loxInstance.Properties[target.Property.Lexeme] = newVal
+	}
+
+	if expr.Postfix {
+		return oldVal, nil
+	}
+	return newVal, nil
+}
+
+func (p *Interpreter) VisitCallExpr(expr *ast.CallExpr) (interface{}, error) {
+	var err error
+
+	// Look up the call binding (i.e. function / class constructor)
+	var callee interface{}
+
+	if callee, err = expr.Callee.Accept(p); err != nil {
+		return nil, err
+	}
+
+	// Arguments are evaluated in the environment of the caller
+	args := make([]Value, len(expr.Arguments))
+	for idx, argExpr := range expr.Arguments {
+		if args[idx], err = argExpr.Accept(p); err != nil {
+			return nil, err
+		}
+	}
+	val, err := p.call(callee, args, expr.Span())
+
+	// Errors raised by the call itself or by a native function
+	// are located at the call expression
+	if rtErr, ok := err.(*RuntimeError); ok && rtErr.Trace == nil {
+		rtErr.Span = expr.Span()
+		rtErr.Trace = p.stackTrace(expr.Span())
+	}
+	return val, err
+}
+
+// CallFunction calls a Lox function, class or native function with evaluated arguments.
+// It allows host code to invoke Lox closures, e.g. as callbacks.
+// When a native function calls back, the call is located at the call of the native.
+func (p *Interpreter) CallFunction(fn Value, args ...Value) (Value, error) {
+	return p.call(fn, args, p.callSite)
+}
+
+func (p *Interpreter) call(fn Value, args []Value, callSite token.Span) (Value, error) {
+	callable, ok := fn.(LoxCallable)
+	if !ok {
+		return nil, &RuntimeError{Reason: fmt.Sprintf("%s is not callable", quote(fn))}
+	}
+
+	// Validate arity
+	if arity := callable.Arity(); arity != VariadicArity && arity != len(args) {
+		return nil, &RuntimeError{Reason: fmt.Sprintf("function call supplies %d arguments, but %d are expected", len(args), arity)}
+	}
+	// Picked up by a LoxFunction to record its stack frame.
+	// It's restored afterwards, so that a native calling back more than once keeps its call site.
+	prevCallSite := p.callSite
+	p.callSite = callSite
+	val, err := callable.Call(p, args)
+	p.callSite = prevCallSite
+	return val, err
+}
+
+func (p *Interpreter) VisitGetPropertyExpr(expr *ast.GetPropertyExpr) (interface{}, error) {
+	var object interface{}
+	var loxInstance *LoxClassInstance
+	var ok bool
+	var err error
+
+	// Get lox class instance
+	if object, err = expr.Object.Accept(p); err != nil {
+		return nil, err
+	}
+	if builtin, isBuiltin := object.(builtinObject); isBuiltin {
+		if method, found := builtin.FindMethod(expr.Property.Lexeme); found {
+			return method, nil
+		}
+		return nil, p.emitRuntimeError(expr, fmt.Sprintf("%s does not have the method %s", typeName(object), expr.Property.Lexeme))
+	}
+	if loxInstance, ok = object.(*LoxClassInstance); !ok {
+		return nil, p.emitRuntimeError(expr, "cannot convert to a LoxClass instance")
+	}
+
+	// Access property from the Lox class instance
+	var val interface{}
+	name := expr.Property.Lexeme
+
+	if val, ok = loxInstance.FindProperty(name); !ok {
+		return nil, p.emitRuntimeError(expr, fmt.Sprintf("class %s does not have the field %s", loxInstance.Class, name))
+	}
+	return val, nil
+}
+
+func (p *Interpreter) VisitSetPropertyExpr(expr *ast.SetPropertyExpr) (interface{}, error) {
+	var object interface{}
+	var loxInstance *LoxClassInstance
+	var val interface{}
+	var ok bool
+	var err error
+
+	// Get lox class instance
+	if object, err = expr.Object.Accept(p); err != nil {
+		return nil, err
+	}
+	if loxInstance, ok = object.(*LoxClassInstance); !ok {
+		return nil, p.emitRuntimeError(expr, "cannot convert to a LoxClass instance")
+	}
+
+	// Evaluate value
+	if val, err = expr.Value.Accept(p); err != nil {
+		return nil, err
+	}
+
+	loxInstance.Properties[expr.Property.Lexeme] = val
+	return val, nil
+}
+
+func (p *Interpreter) VisitLiteralExpr(expr *ast.LiteralExpr) (interface{}, error) {
+	return expr.Value, nil
+}
+
+func (p *Interpreter) VisitInterpolationExpr(expr *ast.InterpolationExpr) (interface{}, error) {
+	var buf strings.Builder
+	for _, part := range expr.Parts {
+		val, err := part.Accept(p)
+		if err != nil {
+			return nil, err
+		}
+		// Display each part as "print" would
+		buf.WriteString(Stringify(val))
+	}
+	return buf.String(), nil
+}
+
+func (p *Interpreter) VisitVariableExpr(expr *ast.VariableExpr) (interface{}, error) {
+	// Design choice: when searching for the value of a variable,
+	// it can be traced back to the outer scopes
+	val, ok := p.CurrEnv.FindBinding(expr.Name.Lexeme, p.ScopeHops[expr])
+	if !ok {
+		return nil, p.emitRuntimeError(expr, fmt.Sprintf("reference an undefined variable: %s", expr.Name.Lexeme))
+	}
+	return val, nil
+}
+
+func (p *Interpreter) VisitThisExpr(expr *ast.ThisExpr) (interface{}, error) {
+	val, ok := p.CurrEnv.FindBinding("this", p.ScopeHops[expr])
+	if !ok {
+		return nil, p.emitRuntimeError(expr, "reference an unbounded \"this\"")
+	}
+	return val, nil
+}
+
+func (p *Interpreter) VisitSuperExpr(expr *ast.SuperExpr) (interface{}, error) {
+	// When `SuperExpr` is evaluated, we must be in a declaration body of a method
+	val, ok := p.CurrEnv.FindBinding("super", p.ScopeHops[expr])
+	if !ok {
+		return nil, p.emitRuntimeError(expr, "reference an unbounded \"super\"")
+	}
+	superClass, ok := val.(*LoxClass)
+	if !ok {
+		return nil, p.emitRuntimeError(expr, "super does not refer to a class")
+	}
+	method, ok := superClass.FindMethod(expr.Property.Lexeme)
+	if !ok {
+		return nil, p.emitRuntimeError(expr, fmt.Sprintf("super class does not have this method: %s", expr.Property.Lexeme))
+	}
+
+	// When a super method is called, it's still binded to the current instance
+	thisInstance, _ := p.CurrEnv.FindBinding("this", p.ScopeHops[expr]-1)
+	method.Closure.CreateBinding("this", thisInstance, true)
+	return method, nil
+}
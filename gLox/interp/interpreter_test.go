@@ -0,0 +1,817 @@
+package interp
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/ast"
+	"github.com/StanleyGY/Lox/gLox/parser"
+	"github.com/StanleyGY/Lox/gLox/resolver"
+	"github.com/StanleyGY/Lox/gLox/scanner"
+	"github.com/StanleyGY/Lox/gLox/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// run scans, parses, resolves and evaluates `src` on `p`
+func run(p *Interpreter, src string) error {
+	s := &scanner.ScannerImpl{}
+	tokens, err := s.Scan(src)
+	if err != nil {
+		return err
+	}
+	stmts, err := (&parser.RDParser{}).Parse(tokens)
+	if err != nil {
+		return err
+	}
+	if err = resolver.MakeResolver(p).Resolve(stmts); err != nil {
+		return err
+	}
+	return p.Evaluate(stmts)
+}
+
+func TestInterpreter(t *testing.T) {
+	t.Run("Test checking type - float64", func(t *testing.T) {
+		p := &Interpreter{}
+		v := float64(3.4)
+		err := p.checkType(&token.Token{}, v, []reflect.Kind{reflect.Float64})
+		assert.NoError(t, err)
+	})
+	t.Run("Test checking type - bool", func(t *testing.T) {
+		p := &Interpreter{}
+		v := true
+		err := p.checkType(&token.Token{}, v, []reflect.Kind{reflect.Float64})
+		fmt.Println(err)
+		assert.Error(t, err)
+	})
+
+	t.Run("Test evaluating unary expr - Bang", func(t *testing.T) {
+		p := &Interpreter{}
+
+		res, _ := p.EvaluateExpr(&ast.UnaryExpr{
+			Operator: &token.Token{Type: token.Bang},
+			Right:    &ast.LiteralExpr{Value: nil},
+		})
+		assert.True(t, res.(bool))
+
+		res, _ = p.EvaluateExpr(&ast.UnaryExpr{
+			Operator: &token.Token{Type: token.Bang},
+			Right:    &ast.LiteralExpr{Value: false},
+		})
+		assert.True(t, res.(bool))
+
+		res, _ = p.EvaluateExpr(&ast.UnaryExpr{
+			Operator: &token.Token{Type: token.Bang},
+			Right:    &ast.LiteralExpr{Value: "test"},
+		})
+		assert.False(t, res.(bool))
+	})
+
+	t.Run("Test evaluating unary expr - Minus", func(t *testing.T) {
+		p := &Interpreter{}
+
+		_, err := p.EvaluateExpr(&ast.UnaryExpr{
+			Operator: &token.Token{Type: token.Minus},
+			Right:    &ast.LiteralExpr{Value: nil},
+		})
+		assert.Error(t, err)
+
+		res, _ := p.EvaluateExpr(&ast.UnaryExpr{
+			Operator: &token.Token{Type: token.Minus},
+			Right:    &ast.LiteralExpr{Value: 7.0},
+		})
+		assert.Equal(t, -7.0, res.(float64))
+	})
+
+	t.Run("Test evaluating binary expr - Plus", func(t *testing.T) {
+		p := &Interpreter{}
+
+		res, _ := p.EvaluateExpr(&ast.BinaryExpr{
+			Operator: &token.Token{Type: token.Plus},
+			Left:     &ast.LiteralExpr{Value: "test"},
+			Right:    &ast.LiteralExpr{Value: "string"},
+		})
+		assert.Equal(t, "teststring", res.(string))
+
+		res, _ = p.EvaluateExpr(&ast.BinaryExpr{
+			Operator: &token.Token{Type: token.Plus},
+			Left:     &ast.LiteralExpr{Value: 1.0},
+			Right:    &ast.LiteralExpr{Value: 2.0},
+		})
+		assert.Equal(t, 3.0, res.(float64))
+	})
+
+	t.Run("Test evaluating binary expr - Greater", func(t *testing.T) {
+		p := &Interpreter{}
+
+		_, err := p.EvaluateExpr(&ast.BinaryExpr{
+			Operator: &token.Token{Type: token.Greater},
+			Left:     &ast.LiteralExpr{Value: "bad"},
+			Right:    &ast.LiteralExpr{Value: "test"},
+		})
+		assert.Error(t, err)
+
+		res, _ := p.EvaluateExpr(&ast.BinaryExpr{
+			Operator: &token.Token{Type: token.Greater},
+			Left:     &ast.LiteralExpr{Value: 1.0},
+			Right:    &ast.LiteralExpr{Value: 2.0},
+		})
+		assert.False(t, res.(bool))
+	})
+
+	t.Run("Test evaluating assign expr", func(t *testing.T) {
+		p := &Interpreter{
+			CurrEnv: &Environment{
+				Bindings: make(map[string]interface{}),
+			},
+		}
+		p.CurrEnv.Bindings["var"] = 1
+
+		_, err := p.EvaluateExpr(&ast.AssignExpr{
+			Name:  &token.Token{Lexeme: "badvar"},
+			Value: &ast.LiteralExpr{Value: 0},
+		})
+		assert.Error(t, err)
+
+		p.EvaluateExpr(&ast.AssignExpr{
+			Name:  &token.Token{Lexeme: "var"},
+			Value: &ast.LiteralExpr{Value: 3},
+		})
+		assert.Equal(t, 3, p.CurrEnv.Bindings["var"])
+	})
+	t.Run("Test script args natives", func(t *testing.T) {
+		var stdout bytes.Buffer
+		p := MakeInterpreter(WithStdout(&stdout))
+		p.DefineScriptArgs([]string{"a", "b"})
+
+		err := run(p, `
+			var xs = args();
+			print xs;
+			xs.push("c");
+			print len(args());
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, "[\"a\", \"b\"]\n2\n", stdout.String())
+	})
+	t.Run("Test prelude natives", func(t *testing.T) {
+		p := MakeInterpreter()
+		call := func(name string, args ...interface{}) (interface{}, error) {
+			var exprs []ast.Expr
+			for _, arg := range args {
+				exprs = append(exprs, &ast.LiteralExpr{Value: arg})
+			}
+			return p.EvaluateExpr(&ast.CallExpr{
+				Callee:    &ast.VariableExpr{Name: &token.Token{Lexeme: name}},
+				Arguments: exprs,
+			})
+		}
+
+		res, _ := call("str", 2.5)
+		assert.Equal(t, "2.5", res)
+		res, _ = call("num", "12")
+		assert.Equal(t, int64(12), res)
+		res, _ = call("num", "1.5")
+		assert.Equal(t, 1.5, res)
+		res, _ = call("num", "twelve")
+		assert.Nil(t, res)
+		res, _ = call("len", "héllo")
+		assert.Equal(t, int64(5), res)
+		res, _ = call("type", true)
+		assert.Equal(t, "bool", res)
+
+		_, err := call("len", 1.0)
+		assert.Error(t, err)
+		_, err = call("exit", 2.0)
+		assert.Equal(t, &RuntimeExit{Code: 2}, err)
+	})
+
+	t.Run("Test defining a variadic native", func(t *testing.T) {
+		p := MakeInterpreter()
+		p.DefineNative("count", VariadicArity, func(interpreter *Interpreter, args []interface{}) (interface{}, error) {
+			return float64(len(args)), nil
+		})
+
+		res, err := p.EvaluateExpr(&ast.CallExpr{
+			Callee:    &ast.VariableExpr{Name: &token.Token{Lexeme: "count"}},
+			Arguments: []ast.Expr{&ast.LiteralExpr{Value: 1.0}, &ast.LiteralExpr{Value: 2.0}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2.0, res)
+	})
+	t.Run("Test calling a Lox function from Go", func(t *testing.T) {
+		p := MakeInterpreter()
+
+		// fun add(a, b) return a + b;
+		err := p.Evaluate([]ast.Stmt{&ast.FuncDeclStmt{
+			Name:   &token.Token{Lexeme: "add"},
+			Params: []*token.Token{{Lexeme: "a"}, {Lexeme: "b"}},
+			Body: &ast.ReturnStmt{Value: &ast.BinaryExpr{
+				Operator: &token.Token{Type: token.Plus},
+				Left:     &ast.VariableExpr{Name: &token.Token{Lexeme: "a"}},
+				Right:    &ast.VariableExpr{Name: &token.Token{Lexeme: "b"}},
+			}},
+		}})
+		assert.NoError(t, err)
+
+		add := p.Globals.Bindings["add"]
+		res, err := p.CallFunction(add, 1.0, 2.0)
+		assert.NoError(t, err)
+		assert.Equal(t, 3.0, res)
+
+		_, err = p.CallFunction(add, 1.0)
+		assert.Error(t, err)
+		_, err = p.CallFunction("not a function")
+		assert.Error(t, err)
+	})
+	t.Run("Test runtime error trace", func(t *testing.T) {
+		p := MakeInterpreter(WithFilename("test.lox"))
+		err := run(p, "fun inner(x) {\n  return -x;\n}\nfun outer() {\n  return inner(\"a\");\n}\nouter();")
+
+		typeErr, ok := err.(RuntimeTypeError)
+		assert.True(t, ok)
+		assert.Equal(t, []StackFrame{
+			{Function: "inner", File: "test.lox", Line: 2},
+			{Function: "outer", File: "test.lox", Line: 5},
+			{File: "test.lox", Line: 7},
+		}, typeErr.Trace)
+		assert.Equal(t, "runtime error: invalid types for operator - on values: \"a\" (string)\n"+
+			"[test.lox:2] in inner()\n[test.lox:5] in outer()\n[test.lox:7] in script", err.Error())
+
+		// Without a file name, only the lines are shown
+		p = MakeInterpreter()
+		err = run(p, "fun f() {\n  return -\"a\";\n}\nf();")
+		assert.True(t, strings.HasSuffix(err.Error(), "\n[line 2] in f()\n[line 4] in script"))
+
+		// Frames are popped while unwinding
+		assert.Empty(t, p.frames)
+	})
+
+	t.Run("Test runtime error raised by a native", func(t *testing.T) {
+		p := MakeInterpreter()
+		err := run(p, "var a = 1;\nlen(a);")
+
+		rtErr, ok := err.(*RuntimeError)
+		assert.True(t, ok)
+		assert.Equal(t, 2, rtErr.Span.Start.Line)
+		assert.Equal(t, []StackFrame{{Line: 2}}, rtErr.Trace)
+	})
+
+	t.Run("Test string interpolation", func(t *testing.T) {
+		var stdout bytes.Buffer
+		p := MakeInterpreter(WithStdout(&stdout))
+		err := run(p, `
+			var name = "Ada";
+			var age = 36;
+			fun greet() { return "hi"; }
+			print "hello ${name}, you are ${age + 1}";
+			print "${greet()}: ${nil} ${true} ${"${age / 8.0}"}";
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello Ada, you are 37\nhi: nil true 4.5\n", stdout.String())
+	})
+
+	t.Run("Test integer and float arithmetic", func(t *testing.T) {
+		var stdout bytes.Buffer
+		p := MakeInterpreter(WithStdout(&stdout))
+		err := run(p, `
+			print 7 / 2;
+			print -7 / 2;
+			print 7 % 3;
+			print -7 % 3;
+			print 7 / 2.0;
+			print 7.5 % 2;
+			print 2 * 1.5;
+			print 1 == 1.0;
+			print 2 > 1.5;
+			print type(9007199254740993);
+			print 9007199254740993 + 1;
+			print int(-3.9);
+			print int("42");
+			print float(3) / 2;
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, "3\n-3\n1\n-1\n3.5\n1.5\n3\ntrue\ntrue\nint\n9007199254740994\n-3\n42\n1.5\n", stdout.String())
+
+		for _, src := range []string{
+			"print 1 / 0;",
+			"print 1 % 0;",
+		} {
+			assert.Error(t, run(p, src), src)
+		}
+		assert.NoError(t, run(p, "print 1 / 0.0;"))
+	})
+
+	t.Run("Test big integers and decimals", func(t *testing.T) {
+		var stdout bytes.Buffer
+		p := MakeInterpreter(WithStdout(&stdout))
+		err := run(p, `
+			var max = 9223372036854775807;
+			print max + 1;
+			print type(max + 1);
+			print max * max;
+			print (max + 1) - 1 == max;
+			print -9223372036854775807 - 1 - 1;
+			print 100000000000000000000 / 3 % 7;
+			print 0.1d + 0.2d;
+			print 0.1d + 0.2d == 0.3d;
+			print 0.1 + 0.2 == 0.3;
+			print type(1.5d);
+			print 10d / 4 + 1;
+			print 1d / 3;
+			print -7.5d % 2;
+			print decimal("19.99") * 3;
+			print decimal(0.1) == 0.1d;
+			print int(12.99d);
+			print int("123456789012345678901234567890");
+			print float(1d / 4);
+			print "total: ${2.50d * 2}";
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"9223372036854775808",
+			"int",
+			"85070591730234615847396907784232501249",
+			"true",
+			"-9223372036854775809",
+			"5",
+			"0.3",
+			"true",
+			"false",
+			"decimal",
+			"3.5",
+			"0.33333333333333333333333333333333",
+			"-1.5",
+			"59.97",
+			"true",
+			"12",
+			"123456789012345678901234567890",
+			"0.25",
+			"total: 5",
+		}, "\n")+"\n", stdout.String())
+
+		// Every operator rejects a float mixed with a decimal
+		for _, op := range []string{"+", "-", "*", "/", "%", "**", "<", "<=", ">", ">=", "==", "!="} {
+			_, isTypeErr := run(p, "print 0.5 "+op+" 0.5d;").(RuntimeTypeError)
+			assert.True(t, isTypeErr, op)
+			_, isTypeErr = run(p, "print 0.5d "+op+" 0.5;").(RuntimeTypeError)
+			assert.True(t, isTypeErr, op)
+		}
+		stdout.Reset()
+		assert.NoError(t, run(p, "print [0.5] == [0.5d]; print [0.1d].contains(0.1);"))
+		assert.Equal(t, "true\nfalse\n", stdout.String())
+		assert.Error(t, run(p, "print 1d / 0;"))
+		assert.Error(t, run(p, "print 100000000000000000000 % 0;"))
+	})
+
+	t.Run("Test exponent and bitwise operators", func(t *testing.T) {
+		var stdout bytes.Buffer
+		p := MakeInterpreter(WithStdout(&stdout))
+		err := run(p, `
+			print 2 ** 10;
+			print 2 ** 3 ** 2;
+			print -2 ** 2;
+			print 2 ** -1;
+			print 2.0 ** 0.5 == 2 ** 0.5;
+			print 2 ** 64;
+			print 1.5d ** 2;
+			print 2d ** -2;
+			print 0xF0 | 0x0F;
+			print 0b1100 & 0b1010;
+			print 0b1100 ^ 0b1010;
+			print ~5;
+			print 1 << 62 << 2;
+			print -16 >> 2;
+			print (1 << 100) >> 99;
+			print 7 % 4 ** 2;
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"1024",
+			"512",
+			"-4",
+			"0.5",
+			"true",
+			"18446744073709551616",
+			"2.25",
+			"0.25",
+			"255",
+			"8",
+			"6",
+			"-6",
+			"18446744073709551616",
+			"-4",
+			"2",
+			"7",
+		}, "\n")+"\n", stdout.String())
+
+		for _, src := range []string{
+			"print 1.5 & 1;",
+			"print ~1.0;",
+			"print 1 << -1;",
+			"print \"a\" ** 2;",
+			"print 2d ** 0.5d;",
+			"print 0d ** -1;",
+		} {
+			assert.Error(t, run(p, src), src)
+		}
+
+		// Results are bounded, except for the trivial bases
+		for src, reason := range map[string]string{
+			"print 2 ** 9999999999;":    "exponent is too large",
+			"print 3 ** (1 << 21);":     "exponent is too large",
+			"print 1.5d ** 9999999999;": "exponent is too large",
+			"print 1 << 9999999999;":    "shift count is too large",
+			"print 1 >> 4294967295;":    "shift count is too large",
+		} {
+			rtErr, ok := run(p, src).(*RuntimeError)
+			if assert.True(t, ok, src) {
+				assert.Equal(t, reason, rtErr.Reason, src)
+			}
+		}
+		stdout.Reset()
+		assert.NoError(t, run(p, "print (-1) ** 9999999999; print 1d ** 9999999999; print 1 << 100000;"))
+		assert.True(t, strings.HasPrefix(stdout.String(), "-1\n1\n"))
+	})
+
+	t.Run("Test update expressions", func(t *testing.T) {
+		var stdout bytes.Buffer
+		p := MakeInterpreter(WithStdout(&stdout))
+		err := run(p, `
+			var i = 5;
+			i += 2; print i;
+			i -= 1; print i;
+			i *= 3; print i;
+			i /= 4; print i;
+			i %= 3; print i;
+			print i++;
+			print i;
+			print ++i;
+			print i--;
+			print --i;
+
+			var s = "a";
+			s += "b";
+			print s;
+
+			class Counter { init() { this.count = 0; } }
+			var calls = 0;
+			var c = Counter();
+			fun get() { calls += 1; return c; }
+			get().count += 10;
+			get().count++;
+			print ++get().count;
+			print c.count;
+			print calls;
+
+			fun counter() {
+				var n = 0;
+				fun next() { return n++; }
+				return next;
+			}
+			var next = counter();
+			next(); next();
+			print next();
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"7", "6", "18", "4", "1", "1", "2", "3", "3", "1", "ab", "12", "12", "3", "2",
+		}, "\n")+"\n", stdout.String())
+
+		err = run(p, `var t = "x"; t -= 1;`)
+		assert.Contains(t, err.Error(), "invalid types for operator -= on values")
+		assert.Error(t, run(p, "undefined += 1;"))
+	})
+
+	t.Run("Test lists", func(t *testing.T) {
+		var stdout bytes.Buffer
+		p := MakeInterpreter(WithStdout(&stdout))
+		err := run(p, `
+			var xs = [3, 1, 2];
+			print xs;
+			print xs[0] + xs[-1];
+			xs[1] = "a";
+			xs[0] += 10;
+			xs[-1]++;
+			print xs;
+			print xs[1:];
+			print xs[:-1];
+			print xs[5:1];
+
+			var ys = [5, 2, 9, 1];
+			ys.sort();
+			print ys;
+			fun desc(a, b) { return a > b; }
+			ys.sort(desc);
+			print ys;
+			ys.push(7);
+			ys.insert(0, 0);
+			print ys;
+			print ys.pop();
+			print ys.remove(1);
+			print ys;
+			print ys.contains(2);
+			print ys.contains(3);
+			print ys.len() + len(ys);
+			print type(ys);
+
+			var alias = ys;
+			alias.push(1);
+			print ys.len();
+			var copy = ys[:];
+			copy.push(1);
+			print ys.len();
+
+			print "héllo"[1] + "héllo"[1:3];
+			if ([]) print "non-empty"; else print "empty";
+			print [1, [2]] == [1, [2.0]];
+			var zs = [1];
+			zs.push(zs);
+			print zs;
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"[3, 1, 2]", "5", `[13, "a", 3]`, `["a", 3]`, `[13, "a"]`, "[]",
+			"[1, 2, 5, 9]", "[9, 5, 2, 1]", "[0, 9, 5, 2, 1, 7]", "7", "9", "[0, 5, 2, 1]",
+			"true", "false", "8", "list", "5", "5", "éél", "empty", "true", "[1, [...]]",
+		}, "\n")+"\n", stdout.String())
+
+		for src, reason := range map[string]string{
+			"[1][1];":                      "list index out of range",
+			"[1][-2] = 0;":                 "list index out of range",
+			"[1][1.0];":                    "indices must be integers, not a float",
+			"\"ab\"[2];":                   "string index out of range",
+			"var s = \"a\"; s[0] = \"b\";": "the elements of a string cannot be assigned",
+			"nil[0];":                      "a nil cannot be indexed",
+			"1[0:];":                       "a int cannot be sliced",
+			"[].pop();":                    "pop() from an empty list",
+			"[1, \"a\"].sort();":           "sort() cannot compare a string with a int",
+			"[].shift();":                  "list does not have the method shift",
+		} {
+			err := run(p, src)
+			assert.ErrorContains(t, err, reason, src)
+		}
+	})
+
+	t.Run("Test maps", func(t *testing.T) {
+		var stdout bytes.Buffer
+		p := MakeInterpreter(WithStdout(&stdout))
+		err := run(p, `
+			var m = {"a": 1, "b": [2], 3: "three",};
+			print m;
+			m["c"] = {};
+			m["a"] += 10;
+			m[3.0] = "THREE";
+			m[1d] = true;
+			print m[1];
+			print m;
+			print m.keys();
+			print m.values();
+			print m.has("b");
+			print m.has("z");
+			print m.delete("b");
+			print m.delete("b");
+			print m.len() + len(m);
+			print type(m);
+
+			class A {}
+			var a = A();
+			var b = A();
+			var n = {a: 1, b: 2, nil: 3, true: 4, false: 5};
+			print n[a] + n[b] + n[nil] + n[true] + n[false];
+			print a == b;
+			print a == a;
+			print [a] == [b];
+			print {a: 1}.has(A());
+			if ({}) print "non-empty"; else print "empty";
+			print {"x": 1, "y": 2} == {"y": 2, "x": 1.0};
+			print {"x": 1} == {"x": 2};
+			var self = {"k": nil};
+			self["k"] = [self];
+			print self;
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			`{"a": 1, "b": [2], 3: "three"}`, "true",
+			`{"a": 11, "b": [2], 3: "THREE", "c": {}, 1: true}`,
+			`["a", "b", 3, "c", 1]`, `[11, [2], "THREE", {}, true]`,
+			"true", "false", "true", "false", "8", "map", "15",
+			"false", "true", "false", "false",
+			"empty", "true", "false",
+			`{"k": [{...}]}`,
+		}, "\n")+"\n", stdout.String())
+
+		for src, reason := range map[string]string{
+			"print {}[\"a\"];":       "map does not have the key \"a\"",
+			"print {[1]: 2};":        "a list cannot be a map key",
+			"var e = {}; e[{}] = 1;": "a map cannot be a map key",
+			"({}).has(0 / 0.0);":     "NaN cannot be a map key",
+			"({}).get(1);":           "map does not have the method get",
+			"({})[0:1];":             "a map cannot be sliced",
+		} {
+			err := run(p, src)
+			assert.ErrorContains(t, err, reason, src)
+		}
+	})
+
+	t.Run("Test for-in loops", func(t *testing.T) {
+		var stdout bytes.Buffer
+		p := MakeInterpreter(WithStdout(&stdout))
+		err := run(p, `
+			for (var x in [1, 2]) print x;
+			for (var k in {"a": 1, "b": 2}) print k;
+			for (var c in "hé") print c;
+			for (var i in range(0, 7, 3)) print i;
+			for (var i in range(3, 0, -1)) {
+				if (i == 1) break;
+				print i;
+			}
+			for (var i in range(2)) print i;
+			print range(4);
+
+			class CountdownIter {
+				init(n) { this.n = n; }
+				done() { return this.n <= 0; }
+				next() {
+					this.n -= 1;
+					return this.n + 1;
+				}
+			}
+			class Countdown {
+				init(n) { this.n = n; }
+				iter() { return CountdownIter(this.n); }
+			}
+			for (var v in Countdown(2)) print v;
+
+			class Letters {
+				init(s) {
+					this.s = s;
+					this.done = s == "";
+				}
+				next() {
+					var first = this.s[0];
+					this.s = this.s[1:];
+					this.done = this.s == "";
+					return first;
+				}
+			}
+			for (var l in Letters("xy")) print l;
+
+			var fs = [];
+			for (var i in range(2)) {
+				fun f() { return i; }
+				fs.push(f);
+			}
+			for (var f in fs) print f();
+
+			var xs = [1];
+			for (var x in xs) if (x < 3) xs.push(x + 1);
+			print xs;
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"1", "2", "a", "b", "h", "é", "0", "3", "6", "3", "2", "0", "1", "range(0, 4, 1)",
+			"2", "1", "x", "y", "0", "1", "[1, 2, 3]",
+		}, "\n")+"\n", stdout.String())
+
+		for src, reason := range map[string]string{
+			"for (var x in 1) print x;":                                    "a int is not iterable",
+			"for (var x in range(1, 2, 0)) print x;":                       "range() step cannot be zero",
+			"for (var x in range(1.5)) print x;":                           "range() expects integers, not a float",
+			"class C {} for (var x in C()) print x;":                       "class C is not an iterator",
+			"class D { iter() { return 1; } } for (var x in D()) print x;": "iter() returned a int instead of an iterator",
+		} {
+			err := run(p, src)
+			assert.ErrorContains(t, err, reason, src)
+		}
+		assert.Error(t, run(p, "for (var x in [1]) print x; print x;"))
+	})
+
+	t.Run("Test continue statements", func(t *testing.T) {
+		var stdout bytes.Buffer
+		p := MakeInterpreter(WithStdout(&stdout))
+		err := run(p, `
+			for (var i = 0; i < 6; i++) {
+				if (i % 2 == 0) continue;
+				print i;
+			}
+			var j = 0;
+			while (j < 5) {
+				j++;
+				if (j == 2) continue;
+				if (j == 4) break;
+				print j;
+			}
+			for (var c in "abc") {
+				if (c == "b") continue;
+				print c;
+			}
+			for (var k = 0; k < 3; k++) {
+				for (var l = 0; l < 3; l++) {
+					if (l == k) continue;
+					if (l > k) break;
+					print "${k}${l}";
+				}
+			}
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{
+			"1", "3", "5", "1", "3", "a", "c", "10", "20", "21",
+		}, "\n")+"\n", stdout.String())
+
+		for _, src := range []string{
+			"continue;",
+			"break;",
+			"while (true) { fun f() { continue; } }",
+			"for (var x in [1]) { fun f() { break; } }",
+		} {
+			err := run(p, src)
+			assert.ErrorContains(t, err, "must be in a loop", src)
+		}
+	})
+
+	t.Run("Test labeled loops", func(t *testing.T) {
+		var stdout bytes.Buffer
+		p := MakeInterpreter(WithStdout(&stdout))
+		err := run(p, `
+			outer: for (var i = 0; i < 3; i++) {
+				for (var j = 0; j < 3; j++) {
+					if (j == 1) continue outer;
+					if (i == 2) break outer;
+					print "${i}${j}";
+				}
+			}
+
+			var n = 0;
+			loop: while (true) {
+				rows: for (var r in range(5)) {
+					for (var c in range(5)) {
+						n++;
+						if (c == 2) continue rows;
+						if (r == 3) break loop;
+					}
+				}
+			}
+			print n;
+
+			fun find(grid, target) {
+				var found = nil;
+				search: for (var row in grid) {
+					for (var cell in row) {
+						if (cell == target) {
+							found = row;
+							break search;
+						}
+					}
+				}
+				return found;
+			}
+			print find([[1, 2], [3, 4]], 3);
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Join([]string{"00", "10", "10", "[3, 4]"}, "\n")+"\n", stdout.String())
+
+		for src, reason := range map[string]string{
+			"while (true) break nope;":                    "label nope does not name an enclosing loop",
+			"a: while (true) {} while (true) continue a;": "label a does not name an enclosing loop",
+			"x: while (true) { x: for (;;) {} }":          "label x is already used by an enclosing loop",
+			"x: while (true) { fun f() { break x; } }":    "break must be in a loop",
+		} {
+			err := run(p, src)
+			assert.ErrorContains(t, err, reason, src)
+		}
+	})
+
+	t.Run("Test runtime error trace through a native callback", func(t *testing.T) {
+		p := MakeInterpreter(WithFilename("test.lox"))
+		err := run(p, "fun lt(a, b) {\n  return -a < -b;\n}\nvar xs = [1, \"a\"];\n\nxs.sort(lt);")
+
+		typeErr, ok := err.(RuntimeTypeError)
+		assert.True(t, ok)
+		assert.Equal(t, []StackFrame{
+			{Function: "lt", File: "test.lox", Line: 2},
+			{File: "test.lox", Line: 6},
+		}, typeErr.Trace)
+		assert.Empty(t, p.frames)
+	})
+
+	t.Run("Test equal numbers are the same map key", func(t *testing.T) {
+		var stdout bytes.Buffer
+		p := MakeInterpreter(WithStdout(&stdout))
+		err := run(p, `
+			print 2 ** 53 + 1 == 2.0 ** 53;
+			print 2 ** 53 + 1 > 2.0 ** 53;
+			print 2 ** 53 == 2.0 ** 53;
+			print 10 ** 400 < 1 / 0.0;
+			print 0 / 0.0 == 0 / 0.0;
+			var m = {2 ** 53 + 1: "int", 2.0 ** 53: "float", 0.1: "float", 0.1d: "decimal"};
+			print m.len();
+			m[2 ** 53] = "replaced";
+			print m[2.0 ** 53];
+			print {0.5: 1} == {0.5d: 1};
+		`)
+		assert.NoError(t, err)
+		assert.Equal(t, "false\ntrue\ntrue\ntrue\nfalse\n4\nreplaced\ntrue\n", stdout.String())
+	})
+}
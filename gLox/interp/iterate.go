@@ -0,0 +1,119 @@
+package interp
+
+import (
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/ast"
+)
+
+/*
+	A for-in loop iterates over the elements of a list, the keys of a map in insertion order,
+	the characters of a string, or the integers of a range.
+
+	It also iterates over a class instance that implements the iterator protocol:
+	the instance provides an iterator through an "iter()" method, or is an iterator itself.
+	Before each step, the loop stops when the "done" property of the iterator is truthy
+	("done" may be a field or a method), and otherwise calls "next()" to get the element.
+*/
+
+// LoxRange is the sequence of integers from `Start` to `End` (excluded), spaced by `Step`
+type LoxRange struct {
+	Start int64
+	End   int64
+	Step  int64
+}
+
+func (r *LoxRange) String() string {
+	return fmt.Sprintf("range(%d, %d, %d)", r.Start, r.End, r.Step)
+}
+
+// iterate calls `body` with each element of `iterable`, until it returns an error
+func (p *Interpreter) iterate(stmt *ast.ForInStmt, iterable Value, body func(element Value) error) error {
+	switch iterable := iterable.(type) {
+	case *LoxList:
+		// The list may grow or shrink while the loop runs
+		for idx := 0; idx < len(iterable.Elements); idx++ {
+			if err := body(iterable.Elements[idx]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *LoxMap:
+		for _, entry := range iterable.Entries() {
+			if err := body(entry.Key); err != nil {
+				return err
+			}
+		}
+		return nil
+	case string:
+		for _, char := range iterable {
+			if err := body(string(char)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *LoxRange:
+		for n := iterable.Start; (iterable.Step > 0 && n < iterable.End) || (iterable.Step < 0 && n > iterable.End); n += iterable.Step {
+			if err := body(n); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *LoxClassInstance:
+		return p.iterateInstance(stmt, iterable, body)
+	}
+	return p.emitRuntimeError(stmt.Iterable, fmt.Sprintf("a %s is not iterable", typeName(iterable)))
+}
+
+// iterateInstance runs the iterator protocol over a class instance
+func (p *Interpreter) iterateInstance(stmt *ast.ForInStmt, instance *LoxClassInstance, body func(element Value) error) error {
+	var done Value
+	var element Value
+	var err error
+
+	iterator := instance
+	if iter, ok := instance.FindProperty("iter"); ok {
+		var val Value
+		if val, err = p.callMethod(stmt, iter); err != nil {
+			return err
+		}
+		if iterator, ok = val.(*LoxClassInstance); !ok {
+			return p.emitRuntimeError(stmt.Iterable, fmt.Sprintf("iter() returned a %s instead of an iterator", typeName(val)))
+		}
+	}
+	next, hasNext := iterator.FindProperty("next")
+	_, hasDone := iterator.FindProperty("done")
+	if !hasNext || !hasDone {
+		return p.emitRuntimeError(stmt.Iterable, fmt.Sprintf("class %s is not an iterator, it needs \"next\" and \"done\"", iterator.Class))
+	}
+
+	for {
+		// "done" is read again at each step, as "next()" usually updates it.
+		// Methods are bound to "this" when they are looked up, so they are looked up at each step too.
+		done, _ = iterator.FindProperty("done")
+		if _, isMethod := done.(LoxCallable); isMethod {
+			if done, err = p.callMethod(stmt, done); err != nil {
+				return err
+			}
+		}
+		if p.isTruthy(done) {
+			return nil
+		}
+		next, _ = iterator.FindProperty("next")
+		if element, err = p.callMethod(stmt, next); err != nil {
+			return err
+		}
+		if err = body(element); err != nil {
+			return err
+		}
+	}
+}
+
+// callMethod calls a method of the iterator protocol without arguments
+func (p *Interpreter) callMethod(stmt *ast.ForInStmt, method Value) (Value, error) {
+	val, err := p.call(method, nil, stmt.Iterable.Span())
+	if rtErr, ok := err.(*RuntimeError); ok && rtErr.Trace == nil {
+		rtErr.Span = stmt.Iterable.Span()
+		rtErr.Trace = p.stackTrace(stmt.Iterable.Span())
+	}
+	return val, err
+}
@@ -0,0 +1,385 @@
+package interp
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/ast"
+	"sort"
+	"unicode/utf8"
+)
+
+// LoxList is a mutable sequence of values. It's shared by reference, like class instances.
+type LoxList struct {
+	Elements []Value
+}
+
+func (l *LoxList) String() string {
+	var buf bytes.Buffer
+	formatValue(&buf, l, map[interface{}]bool{})
+	return buf.String()
+}
+
+// formatValue writes a value nested in a list or a map. Strings are quoted,
+// and a list or a map that contains itself is written "[...]" or "{...}".
+// `seen` holds the lists and maps being written.
+func formatValue(buf *bytes.Buffer, v Value, seen map[interface{}]bool) {
+	switch v := v.(type) {
+	case *LoxList:
+		if seen[v] {
+			buf.WriteString("[...]")
+			return
+		}
+		seen[v] = true
+		defer delete(seen, v)
+
+		buf.WriteString("[")
+		for idx, element := range v.Elements {
+			if idx > 0 {
+				buf.WriteString(", ")
+			}
+			formatValue(buf, element, seen)
+		}
+		buf.WriteString("]")
+	case *LoxMap:
+		if seen[v] {
+			buf.WriteString("{...}")
+			return
+		}
+		seen[v] = true
+		defer delete(seen, v)
+
+		buf.WriteString("{")
+		for idx, entry := range v.Entries() {
+			if idx > 0 {
+				buf.WriteString(", ")
+			}
+			formatValue(buf, entry.Key, seen)
+			buf.WriteString(": ")
+			formatValue(buf, entry.Value, seen)
+		}
+		buf.WriteString("}")
+	default:
+		buf.WriteString(quote(v))
+	}
+}
+
+// FindMethod returns a method of the list, bound to it
+func (l *LoxList) FindMethod(name string) (*NativeFunction, bool) {
+	var arity int
+	var fn NativeFn
+
+	switch name {
+	case "push":
+		arity, fn = 1, l.push
+	case "pop":
+		arity, fn = 0, l.pop
+	case "len":
+		arity, fn = 0, l.len
+	case "insert":
+		arity, fn = 2, l.insert
+	case "remove":
+		arity, fn = 1, l.remove
+	case "contains":
+		arity, fn = 1, l.contains
+	case "sort":
+		arity, fn = VariadicArity, l.sort
+	default:
+		return nil, false
+	}
+	return &NativeFunction{name: name, arity: arity, fn: fn}, true
+}
+
+// push(value) appends a value to the end of the list
+func (l *LoxList) push(interpreter *Interpreter, args []Value) (Value, error) {
+	l.Elements = append(l.Elements, args[0])
+	return nil, nil
+}
+
+// pop() removes and returns the last element of the list
+func (l *LoxList) pop(interpreter *Interpreter, args []Value) (Value, error) {
+	if len(l.Elements) == 0 {
+		return nil, &RuntimeError{Reason: "pop() from an empty list"}
+	}
+	last := l.Elements[len(l.Elements)-1]
+	l.Elements = l.Elements[:len(l.Elements)-1]
+	return last, nil
+}
+
+// len() returns the number of elements in the list
+func (l *LoxList) len(interpreter *Interpreter, args []Value) (Value, error) {
+	return int64(len(l.Elements)), nil
+}
+
+// insert(index, value) inserts a value before the element at `index`.
+// An index equal to the length of the list appends the value.
+func (l *LoxList) insert(interpreter *Interpreter, args []Value) (Value, error) {
+	idx, ok := args[0].(int64)
+	if !ok {
+		return nil, &RuntimeError{Reason: fmt.Sprintf("insert() expects an integer index, not a %s", typeName(args[0]))}
+	}
+	if idx < 0 {
+		idx += int64(len(l.Elements))
+	}
+	if idx < 0 || idx > int64(len(l.Elements)) {
+		return nil, &RuntimeError{Reason: "list index out of range"}
+	}
+	l.Elements = append(l.Elements, nil)
+	copy(l.Elements[idx+1:], l.Elements[idx:])
+	l.Elements[idx] = args[1]
+	return nil, nil
+}
+
+// remove(index) removes and returns the element at `index`
+func (l *LoxList) remove(interpreter *Interpreter, args []Value) (Value, error) {
+	idx, ok := args[0].(int64)
+	if !ok {
+		return nil, &RuntimeError{Reason: fmt.Sprintf("remove() expects an integer index, not a %s", typeName(args[0]))}
+	}
+	pos, ok := elementIndex(idx, len(l.Elements))
+	if !ok {
+		return nil, &RuntimeError{Reason: "list index out of range"}
+	}
+	removed := l.Elements[pos]
+	l.Elements = append(l.Elements[:pos], l.Elements[pos+1:]...)
+	return removed, nil
+}
+
+// contains(value) checks whether an element of the list equals `value`
+func (l *LoxList) contains(interpreter *Interpreter, args []Value) (Value, error) {
+	for _, element := range l.Elements {
+		if isEqual(element, args[0]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sort([less]) sorts the list in place. Without a comparator, the elements must be all
+// numbers or all strings. `less(a, b)` returns whether `a` goes before `b`.
+func (l *LoxList) sort(interpreter *Interpreter, args []Value) (Value, error) {
+	var err error
+
+	if len(args) > 1 {
+		return nil, &RuntimeError{Reason: "sort() accepts at most one argument"}
+	}
+	less := func(a Value, b Value) bool {
+		var ok bool
+		if ok, err = compareValues(a, b); err != nil {
+			return false
+		}
+		return ok
+	}
+	if len(args) == 1 {
+		less = func(a Value, b Value) bool {
+			var result Value
+			if result, err = interpreter.CallFunction(args[0], a, b); err != nil {
+				return false
+			}
+			return interpreter.isTruthy(result)
+		}
+	}
+
+	// The comparator may fail on any pair, so sort a copy and stop at the first error
+	sorted := append([]Value(nil), l.Elements...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return err == nil && less(sorted[i], sorted[j])
+	})
+	if err != nil {
+		return nil, err
+	}
+	l.Elements = sorted
+	return nil, nil
+}
+
+// compareValues returns whether `a` is less than `b`, for two numbers or two strings
+func compareValues(a Value, b Value) (bool, error) {
+	if aStr, ok := a.(string); ok {
+		if bStr, ok := b.(string); ok {
+			return aStr < bStr, nil
+		}
+	}
+	if isNumber(a) && isNumber(b) {
+		_, aIsFloat := a.(float64)
+		_, bIsFloat := b.(float64)
+		switch {
+		case mixesFloatAndDecimal(a, b):
+		case aIsFloat || bIsFloat:
+			return toFloat(a) < toFloat(b), nil
+		default:
+			return toDecimal(a).Cmp(toDecimal(b)) < 0, nil
+		}
+	}
+	return false, &RuntimeError{Reason: fmt.Sprintf("sort() cannot compare a %s with a %s", typeName(a), typeName(b))}
+}
+
+// elementIndex converts a possibly negative index to a position in a sequence of `length` elements
+func elementIndex(idx int64, length int) (int, bool) {
+	if idx < 0 {
+		idx += int64(length)
+	}
+	if idx < 0 || idx >= int64(length) {
+		return 0, false
+	}
+	return int(idx), true
+}
+
+// sliceBounds converts the optional bounds of a slice to positions in a sequence of `length` elements.
+// Negative bounds count from the end, and bounds out of the sequence are clamped.
+func (p *Interpreter) sliceBounds(expr *ast.SliceExpr, start Value, end Value, length int) (int, int, error) {
+	bounds := []int{0, length}
+	for idx, bound := range []Value{start, end} {
+		if bound == nil {
+			continue
+		}
+		n, ok := bound.(int64)
+		if !ok {
+			return 0, 0, p.emitRuntimeError(expr, fmt.Sprintf("slice bounds must be integers, not a %s", typeName(bound)))
+		}
+		if n < 0 {
+			n += int64(length)
+		}
+		bounds[idx] = int(max(0, min(n, int64(length))))
+	}
+	return bounds[0], max(bounds[0], bounds[1]), nil
+}
+
+// getIndex reads the element of a list, the character of a string, or the value of a map key
+func (p *Interpreter) getIndex(expr ast.Expr, object Value, index Value) (Value, error) {
+	if m, ok := object.(*LoxMap); ok {
+		val, found, err := m.Get(index)
+		if err != nil {
+			return nil, p.emitRuntimeError(expr, err.(*RuntimeError).Reason)
+		}
+		if !found {
+			return nil, p.emitRuntimeError(expr, fmt.Sprintf("map does not have the key %s", quote(index)))
+		}
+		return val, nil
+	}
+
+	idx, ok := index.(int64)
+	if !ok {
+		return nil, p.emitRuntimeError(expr, fmt.Sprintf("indices must be integers, not a %s", typeName(index)))
+	}
+	switch object := object.(type) {
+	case *LoxList:
+		pos, ok := elementIndex(idx, len(object.Elements))
+		if !ok {
+			return nil, p.emitRuntimeError(expr, "list index out of range")
+		}
+		return object.Elements[pos], nil
+	case string:
+		runes := []rune(object)
+		pos, ok := elementIndex(idx, len(runes))
+		if !ok {
+			return nil, p.emitRuntimeError(expr, "string index out of range")
+		}
+		return string(runes[pos]), nil
+	}
+	return nil, p.emitRuntimeError(expr, fmt.Sprintf("a %s cannot be indexed", typeName(object)))
+}
+
+// setIndex writes the element of a list at `index`, or the value of a map key
+func (p *Interpreter) setIndex(expr ast.Expr, object Value, index Value, val Value) error {
+	if m, ok := object.(*LoxMap); ok {
+		if err := m.Set(index, val); err != nil {
+			return p.emitRuntimeError(expr, err.(*RuntimeError).Reason)
+		}
+		return nil
+	}
+
+	list, ok := object.(*LoxList)
+	if !ok {
+		return p.emitRuntimeError(expr, fmt.Sprintf("the elements of a %s cannot be assigned", typeName(object)))
+	}
+	idx, ok := index.(int64)
+	if !ok {
+		return p.emitRuntimeError(expr, fmt.Sprintf("indices must be integers, not a %s", typeName(index)))
+	}
+	pos, ok := elementIndex(idx, len(list.Elements))
+	if !ok {
+		return p.emitRuntimeError(expr, "list index out of range")
+	}
+	list.Elements[pos] = val
+	return nil
+}
+
+func (p *Interpreter) VisitListExpr(expr *ast.ListExpr) (interface{}, error) {
+	var err error
+
+	elements := make([]Value, len(expr.Elements))
+	for idx, element := range expr.Elements {
+		if elements[idx], err = element.Accept(p); err != nil {
+			return nil, err
+		}
+	}
+	return &LoxList{Elements: elements}, nil
+}
+
+func (p *Interpreter) VisitIndexGetExpr(expr *ast.IndexGetExpr) (interface{}, error) {
+	var object interface{}
+	var index interface{}
+	var err error
+
+	if object, err = expr.Object.Accept(p); err != nil {
+		return nil, err
+	}
+	if index, err = expr.Index.Accept(p); err != nil {
+		return nil, err
+	}
+	return p.getIndex(expr, object, index)
+}
+
+func (p *Interpreter) VisitIndexSetExpr(expr *ast.IndexSetExpr) (interface{}, error) {
+	var object interface{}
+	var index interface{}
+	var val interface{}
+	var err error
+
+	if object, err = expr.Object.Accept(p); err != nil {
+		return nil, err
+	}
+	if index, err = expr.Index.Accept(p); err != nil {
+		return nil, err
+	}
+	if val, err = expr.Value.Accept(p); err != nil {
+		return nil, err
+	}
+	if err = p.setIndex(expr, object, index, val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+func (p *Interpreter) VisitSliceExpr(expr *ast.SliceExpr) (interface{}, error) {
+	var object interface{}
+	var bounds [2]interface{}
+	var err error
+
+	if object, err = expr.Object.Accept(p); err != nil {
+		return nil, err
+	}
+	for idx, bound := range []ast.Expr{expr.Start, expr.End} {
+		if bound == nil {
+			continue
+		}
+		if bounds[idx], err = bound.Accept(p); err != nil {
+			return nil, err
+		}
+	}
+
+	switch object := object.(type) {
+	case *LoxList:
+		start, end, err := p.sliceBounds(expr, bounds[0], bounds[1], len(object.Elements))
+		if err != nil {
+			return nil, err
+		}
+		return &LoxList{Elements: append([]Value(nil), object.Elements[start:end]...)}, nil
+	case string:
+		start, end, err := p.sliceBounds(expr, bounds[0], bounds[1], utf8.RuneCountInString(object))
+		if err != nil {
+			return nil, err
+		}
+		return string([]rune(object)[start:end]), nil
+	}
+	return nil, p.emitRuntimeError(expr, fmt.Sprintf("a %s cannot be sliced", typeName(object)))
+}
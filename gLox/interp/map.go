@@ -0,0 +1,196 @@
+package interp
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/ast"
+	"math"
+	"math/big"
+)
+
+/*
+	Map keys are strings, numbers, booleans, nil or class instances.
+	Numbers with the same exact value are the same key, so 1, 1.0 and 1d are interchangeable.
+	Class instances are compared by identity. Lists and maps cannot be keys, as they are mutable.
+*/
+
+// numberKey is the exact value of a numeric key, as a fraction
+type numberKey string
+
+type mapEntry struct {
+	Key   Value
+	Value Value
+}
+
+// LoxMap associates keys to values, and remembers the insertion order of the keys.
+// It's shared by reference, like lists.
+type LoxMap struct {
+	entries map[interface{}]*mapEntry
+	// Hashes of the keys in insertion order
+	order []interface{}
+}
+
+func MakeLoxMap() *LoxMap {
+	return &LoxMap{entries: make(map[interface{}]*mapEntry)}
+}
+
+// hashKey converts a key to a comparable Go value, so that equal keys have the same hash
+func hashKey(key Value) (interface{}, error) {
+	switch k := key.(type) {
+	case nil, bool, string, *LoxClassInstance:
+		return k, nil
+	case int64:
+		return numberKey(big.NewRat(k, 1).RatString()), nil
+	case *big.Int:
+		return numberKey(new(big.Rat).SetInt(k).RatString()), nil
+	case *big.Rat:
+		return numberKey(k.RatString()), nil
+	case float64:
+		if math.IsNaN(k) || math.IsInf(k, 0) {
+			return nil, &RuntimeError{Reason: fmt.Sprintf("%v cannot be a map key", k)}
+		}
+		return numberKey(new(big.Rat).SetFloat64(k).RatString()), nil
+	}
+	return nil, &RuntimeError{Reason: fmt.Sprintf("a %s cannot be a map key", typeName(key))}
+}
+
+func (m *LoxMap) Len() int {
+	return len(m.order)
+}
+
+// Get finds the value of a key
+func (m *LoxMap) Get(key Value) (Value, bool, error) {
+	hash, err := hashKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	if entry, ok := m.entries[hash]; ok {
+		return entry.Value, true, nil
+	}
+	return nil, false, nil
+}
+
+// Set adds or replaces the value of a key. A replaced key keeps its position.
+func (m *LoxMap) Set(key Value, val Value) error {
+	hash, err := hashKey(key)
+	if err != nil {
+		return err
+	}
+	if entry, ok := m.entries[hash]; ok {
+		entry.Value = val
+		return nil
+	}
+	m.entries[hash] = &mapEntry{Key: key, Value: val}
+	m.order = append(m.order, hash)
+	return nil
+}
+
+// Delete removes a key, and returns whether it was present
+func (m *LoxMap) Delete(key Value) (bool, error) {
+	hash, err := hashKey(key)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := m.entries[hash]; !ok {
+		return false, nil
+	}
+	delete(m.entries, hash)
+	for idx, h := range m.order {
+		if h == hash {
+			m.order = append(m.order[:idx], m.order[idx+1:]...)
+			break
+		}
+	}
+	return true, nil
+}
+
+// Entries lists the entries in insertion order
+func (m *LoxMap) Entries() []*mapEntry {
+	entries := make([]*mapEntry, len(m.order))
+	for idx, hash := range m.order {
+		entries[idx] = m.entries[hash]
+	}
+	return entries
+}
+
+func (m *LoxMap) String() string {
+	var buf bytes.Buffer
+	formatValue(&buf, m, map[interface{}]bool{})
+	return buf.String()
+}
+
+// FindMethod returns a method of the map, bound to it
+func (m *LoxMap) FindMethod(name string) (*NativeFunction, bool) {
+	var arity int
+	var fn NativeFn
+
+	switch name {
+	case "keys":
+		arity, fn = 0, m.keys
+	case "values":
+		arity, fn = 0, m.values
+	case "has":
+		arity, fn = 1, m.has
+	case "delete":
+		arity, fn = 1, m.delete
+	case "len":
+		arity, fn = 0, m.len
+	default:
+		return nil, false
+	}
+	return &NativeFunction{name: name, arity: arity, fn: fn}, true
+}
+
+// keys() returns a list of the keys in insertion order
+func (m *LoxMap) keys(interpreter *Interpreter, args []Value) (Value, error) {
+	keys := make([]Value, 0, m.Len())
+	for _, entry := range m.Entries() {
+		keys = append(keys, entry.Key)
+	}
+	return &LoxList{Elements: keys}, nil
+}
+
+// values() returns a list of the values, in the insertion order of their keys
+func (m *LoxMap) values(interpreter *Interpreter, args []Value) (Value, error) {
+	values := make([]Value, 0, m.Len())
+	for _, entry := range m.Entries() {
+		values = append(values, entry.Value)
+	}
+	return &LoxList{Elements: values}, nil
+}
+
+// has(key) checks whether the map contains a key
+func (m *LoxMap) has(interpreter *Interpreter, args []Value) (Value, error) {
+	_, ok, err := m.Get(args[0])
+	return ok, err
+}
+
+// delete(key) removes a key, and returns whether it was present
+func (m *LoxMap) delete(interpreter *Interpreter, args []Value) (Value, error) {
+	return m.Delete(args[0])
+}
+
+// len() returns the number of keys in the map
+func (m *LoxMap) len(interpreter *Interpreter, args []Value) (Value, error) {
+	return int64(m.Len()), nil
+}
+
+func (p *Interpreter) VisitMapExpr(expr *ast.MapExpr) (interface{}, error) {
+	var key interface{}
+	var val interface{}
+	var err error
+
+	m := MakeLoxMap()
+	for idx := range expr.Keys {
+		if key, err = expr.Keys[idx].Accept(p); err != nil {
+			return nil, err
+		}
+		if val, err = expr.Values[idx].Accept(p); err != nil {
+			return nil, err
+		}
+		if err = m.Set(key, val); err != nil {
+			return nil, p.emitRuntimeError(expr.Keys[idx], err.(*RuntimeError).Reason)
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,66 @@
+package interp
+
+import (
+	"fmt"
+	"os"
+)
+
+// VariadicArity is the arity of a native function that accepts any number of arguments.
+// Such a function validates its arguments by itself.
+const VariadicArity = -1
+
+// NativeFn is the Go implementation of a native function
+type NativeFn func(interpreter *Interpreter, args []Value) (Value, error)
+
+// NativeFunction is a function implemented in Go and exposed to Lox programs
+type NativeFunction struct {
+	name  string
+	arity int
+	fn    NativeFn
+}
+
+func (f *NativeFunction) Call(interpreter *Interpreter, args []Value) (Value, error) {
+	return f.fn(interpreter, args)
+}
+
+func (f *NativeFunction) Arity() int {
+	return f.arity
+}
+
+func (f *NativeFunction) String() string {
+	return fmt.Sprintf("<native fn %s>", f.name)
+}
+
+// DefineNative binds a Go function to `name` in the global environment.
+// Use VariadicArity as `arity` to accept any number of arguments.
+func (p *Interpreter) DefineNative(name string, arity int, fn NativeFn) {
+	p.Globals.CreateBinding(name, &NativeFunction{name: name, arity: arity, fn: fn}, true)
+}
+
+// DefineScriptArgs exposes the command-line arguments of a script to the Lox program
+// through "args()", which evaluates to a list of strings
+func (p *Interpreter) DefineScriptArgs(args []string) {
+	p.DefineNative("args", 0, func(interpreter *Interpreter, argv []Value) (Value, error) {
+		// A new list at each call, as the program may modify it
+		elements := make([]Value, len(args))
+		for idx, arg := range args {
+			elements[idx] = arg
+		}
+		return &LoxList{Elements: elements}, nil
+	})
+}
+
+// DefineEnv exposes the environment variables of the process to the Lox program
+// through "env(name)", which evaluates to nil for an unset variable
+func (p *Interpreter) DefineEnv() {
+	p.DefineNative("env", 1, func(interpreter *Interpreter, argv []Value) (Value, error) {
+		name, ok := argv[0].(string)
+		if !ok {
+			return nil, &RuntimeError{Reason: "env() expects a string"}
+		}
+		if val, found := os.LookupEnv(name); found {
+			return val, nil
+		}
+		return nil, nil
+	})
+}
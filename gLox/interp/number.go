@@ -0,0 +1,465 @@
+package interp
+
+import (
+	"cmp"
+	"github.com/StanleyGY/Lox/gLox/ast"
+	"github.com/StanleyGY/Lox/gLox/token"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+/*
+	Numbers are integers (int64, or *big.Int when they overflow it),
+	floats (float64) or exact decimals (*big.Rat).
+	An operation on two integers gives an integer: "/" truncates toward zero
+	and "%" takes the sign of the dividend, while "**" gives a float for a
+	negative exponent. Bitwise operators only accept integers. An integer is promoted when the
+	other operand is a float or a decimal. Floats and decimals cannot be mixed by any operator,
+	"==" and "!=" included, as this would lose the exactness of decimals. Inside lists and maps,
+	numbers are equal when their exact values are, like map keys.
+	Big values are never mutated, and a *big.Int always overflows int64.
+*/
+
+// maxIntBits bounds the size of the integers computed by "**" and "<<",
+// so that a typo cannot exhaust the memory
+const maxIntBits = 1 << 20
+
+// powTooLarge checks whether `base ** exp` would exceed maxIntBits, for a non-negative `exp`
+func powTooLarge(base *big.Int, exp *big.Int) bool {
+	if base.CmpAbs(big.NewInt(1)) <= 0 {
+		return false
+	}
+	return !exp.IsInt64() || exp.Int64() > maxIntBits || int64(base.BitLen()-1)*exp.Int64() > maxIntBits
+}
+
+func isNumber(v Value) bool {
+	switch v.(type) {
+	case int64, float64, *big.Int, *big.Rat:
+		return true
+	}
+	return false
+}
+
+// mixesFloatAndDecimal checks whether one value is a float and the other a decimal
+func mixesFloatAndDecimal(left Value, right Value) bool {
+	_, lIsFloat := left.(float64)
+	_, rIsFloat := right.(float64)
+	_, lIsDecimal := left.(*big.Rat)
+	_, rIsDecimal := right.(*big.Rat)
+	return (lIsFloat && rIsDecimal) || (lIsDecimal && rIsFloat)
+}
+
+func toFloat(v Value) float64 {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return f
+	case *big.Rat:
+		f, _ := n.Float64()
+		return f
+	}
+	return math.NaN()
+}
+
+// toInt converts an integer, or a float without fractional part, to an int64
+func toInt(v Value) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case float64:
+		if n == math.Trunc(n) && n >= math.MinInt64 && n < math.MaxInt64 {
+			return int64(n), true
+		}
+	}
+	return 0, false
+}
+
+func isInteger(v Value) bool {
+	switch v.(type) {
+	case int64, *big.Int:
+		return true
+	}
+	return false
+}
+
+func toBigInt(v Value) *big.Int {
+	if n, ok := v.(int64); ok {
+		return big.NewInt(n)
+	}
+	return v.(*big.Int)
+}
+
+func toDecimal(v Value) *big.Rat {
+	switch n := v.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(n)
+	case *big.Int:
+		return new(big.Rat).SetInt(n)
+	}
+	return v.(*big.Rat)
+}
+
+// compareNumbers compares two numbers by their exact values, so that it agrees with
+// the hashes of map keys. It returns false when a number is NaN, which is unordered.
+func compareNumbers(left Value, right Value) (int, bool) {
+	lf, lIsFloat := left.(float64)
+	rf, rIsFloat := right.(float64)
+	switch {
+	case (lIsFloat && math.IsNaN(lf)) || (rIsFloat && math.IsNaN(rf)):
+		return 0, false
+	case lIsFloat && rIsFloat:
+		return cmp.Compare(lf, rf), true
+	case lIsFloat && math.IsInf(lf, 0):
+		return int(math.Copysign(1, lf)), true
+	case rIsFloat && math.IsInf(rf, 0):
+		return -int(math.Copysign(1, rf)), true
+	}
+	return toExact(left).Cmp(toExact(right)), true
+}
+
+// toExact converts a finite number to its exact value
+func toExact(v Value) *big.Rat {
+	if f, ok := v.(float64); ok {
+		return new(big.Rat).SetFloat64(f)
+	}
+	return toDecimal(v)
+}
+
+// normalizeInt demotes a *big.Int to an int64 when it fits
+func normalizeInt(n *big.Int) Value {
+	if n.IsInt64() {
+		return n.Int64()
+	}
+	return n
+}
+
+// isEqual compares two values. Numbers are compared by their exact values, whatever their types,
+// and lists and maps are compared element by element.
+// Class instances are compared by identity, as map keys are.
+func isEqual(left Value, right Value) bool {
+	if l, ok := left.(*LoxClassInstance); ok {
+		r, ok := right.(*LoxClassInstance)
+		return ok && l == r
+	}
+	if l, ok := left.(*LoxMap); ok {
+		r, ok := right.(*LoxMap)
+		if !ok || l.Len() != r.Len() {
+			return false
+		}
+		if l == r {
+			return true
+		}
+		for _, entry := range l.Entries() {
+			val, found, _ := r.Get(entry.Key)
+			if !found || !isEqual(entry.Value, val) {
+				return false
+			}
+		}
+		return true
+	}
+	if l, ok := left.(*LoxList); ok {
+		r, ok := right.(*LoxList)
+		if !ok || len(l.Elements) != len(r.Elements) {
+			return false
+		}
+		if l == r {
+			return true
+		}
+		for idx := range l.Elements {
+			if !isEqual(l.Elements[idx], r.Elements[idx]) {
+				return false
+			}
+		}
+		return true
+	}
+	if isNumber(left) && isNumber(right) {
+		order, ok := compareNumbers(left, right)
+		return ok && order == 0
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// checkNumbers makes sure that all `vals` are numbers
+func (p *Interpreter) checkNumbers(op *token.Token, vals ...Value) error {
+	for _, v := range vals {
+		if !isNumber(v) {
+			return RuntimeTypeError{Operator: op, Vals: vals, Trace: p.stackTrace(op.Span)}
+		}
+	}
+	return nil
+}
+
+// checkIntegers makes sure that all `vals` are integers
+func (p *Interpreter) checkIntegers(op *token.Token, vals ...Value) error {
+	for _, v := range vals {
+		if !isInteger(v) {
+			return RuntimeTypeError{Operator: op, Vals: vals, Trace: p.stackTrace(op.Span)}
+		}
+	}
+	return nil
+}
+
+// arithmetic applies the operator of `expr` on two numbers
+func (p *Interpreter) arithmetic(expr *ast.BinaryExpr, left Value, right Value) (Value, error) {
+	_, lIsFloat := left.(float64)
+	_, rIsFloat := right.(float64)
+	_, lIsDecimal := left.(*big.Rat)
+	_, rIsDecimal := right.(*big.Rat)
+
+	switch {
+	case mixesFloatAndDecimal(left, right):
+		return nil, RuntimeTypeError{Operator: expr.Operator, Vals: []interface{}{left, right}, Trace: p.stackTrace(expr.Operator.Span)}
+	case lIsFloat || rIsFloat:
+		switch expr.Operator.Type {
+		case token.Greater, token.GreaterEqual, token.Less, token.LessEqual:
+			return compareOperator(expr.Operator, left, right), nil
+		}
+		return floatArithmetic(expr.Operator, toFloat(left), toFloat(right))
+	case lIsDecimal || rIsDecimal:
+		return p.decimalArithmetic(expr, toDecimal(left), toDecimal(right))
+	}
+
+	l, lIsInt := left.(int64)
+	r, rIsInt := right.(int64)
+	if lIsInt && rIsInt {
+		return p.intArithmetic(expr, l, r)
+	}
+	return p.bigIntArithmetic(expr, toBigInt(left), toBigInt(right))
+}
+
+// intArithmetic computes on int64 and falls back on *big.Int on overflow
+func (p *Interpreter) intArithmetic(expr *ast.BinaryExpr, l int64, r int64) (Value, error) {
+	switch expr.Operator.Type {
+	case token.Plus:
+		sum := l + r
+		if (r > 0 && sum < l) || (r < 0 && sum > l) {
+			break
+		}
+		return sum, nil
+	case token.Minus:
+		diff := l - r
+		if (r > 0 && diff > l) || (r < 0 && diff < l) {
+			break
+		}
+		return diff, nil
+	case token.Star:
+		if l == 0 || r == 0 {
+			return int64(0), nil
+		}
+		prod := l * r
+		if prod/r != l || (l == -1 && r == math.MinInt64) || (r == -1 && l == math.MinInt64) {
+			break
+		}
+		return prod, nil
+	case token.Slash:
+		if r == 0 {
+			return nil, p.emitRuntimeError(expr, "division by zero")
+		}
+		if l == math.MinInt64 && r == -1 {
+			break
+		}
+		return l / r, nil
+	case token.Percent:
+		if r == 0 {
+			return nil, p.emitRuntimeError(expr, "division by zero")
+		}
+		return l % r, nil
+	case token.StarStar:
+		if r < 0 {
+			return math.Pow(float64(l), float64(r)), nil
+		}
+	case token.Greater:
+		return l > r, nil
+	case token.GreaterEqual:
+		return l >= r, nil
+	case token.Less:
+		return l < r, nil
+	case token.LessEqual:
+		return l <= r, nil
+	}
+	return p.bigIntArithmetic(expr, big.NewInt(l), big.NewInt(r))
+}
+
+func (p *Interpreter) bigIntArithmetic(expr *ast.BinaryExpr, l *big.Int, r *big.Int) (Value, error) {
+	switch expr.Operator.Type {
+	case token.Plus:
+		return normalizeInt(new(big.Int).Add(l, r)), nil
+	case token.Minus:
+		return normalizeInt(new(big.Int).Sub(l, r)), nil
+	case token.Star:
+		return normalizeInt(new(big.Int).Mul(l, r)), nil
+	case token.Slash:
+		if r.Sign() == 0 {
+			return nil, p.emitRuntimeError(expr, "division by zero")
+		}
+		return normalizeInt(new(big.Int).Quo(l, r)), nil
+	case token.Percent:
+		if r.Sign() == 0 {
+			return nil, p.emitRuntimeError(expr, "division by zero")
+		}
+		return normalizeInt(new(big.Int).Rem(l, r)), nil
+	case token.StarStar:
+		if r.Sign() < 0 {
+			return math.Pow(toFloat(l), toFloat(r)), nil
+		}
+		if powTooLarge(l, r) {
+			return nil, p.emitRuntimeError(expr, "exponent is too large")
+		}
+		return normalizeInt(new(big.Int).Exp(l, r, nil)), nil
+	case token.Greater:
+		return l.Cmp(r) > 0, nil
+	case token.GreaterEqual:
+		return l.Cmp(r) >= 0, nil
+	case token.Less:
+		return l.Cmp(r) < 0, nil
+	case token.LessEqual:
+		return l.Cmp(r) <= 0, nil
+	}
+	return nil, RuntimeTypeError{Operator: expr.Operator, Vals: []interface{}{l, r}, Trace: p.stackTrace(expr.Operator.Span)}
+}
+
+func (p *Interpreter) decimalArithmetic(expr *ast.BinaryExpr, l *big.Rat, r *big.Rat) (Value, error) {
+	switch expr.Operator.Type {
+	case token.Plus:
+		return new(big.Rat).Add(l, r), nil
+	case token.Minus:
+		return new(big.Rat).Sub(l, r), nil
+	case token.Star:
+		return new(big.Rat).Mul(l, r), nil
+	case token.Slash:
+		if r.Sign() == 0 {
+			return nil, p.emitRuntimeError(expr, "division by zero")
+		}
+		return new(big.Rat).Quo(l, r), nil
+	case token.Percent:
+		if r.Sign() == 0 {
+			return nil, p.emitRuntimeError(expr, "division by zero")
+		}
+		// l - r * trunc(l / r), so that the result takes the sign of `l`
+		quo := new(big.Rat).Quo(l, r)
+		trunc := new(big.Int).Quo(quo.Num(), quo.Denom())
+		return new(big.Rat).Sub(l, new(big.Rat).Mul(r, new(big.Rat).SetInt(trunc))), nil
+	case token.StarStar:
+		if !r.IsInt() {
+			return nil, p.emitRuntimeError(expr, "the exponent of a decimal must be an integer")
+		}
+		if l.Sign() == 0 && r.Sign() < 0 {
+			return nil, p.emitRuntimeError(expr, "division by zero")
+		}
+		exp := new(big.Int).Abs(r.Num())
+		if powTooLarge(l.Num(), exp) || powTooLarge(l.Denom(), exp) {
+			return nil, p.emitRuntimeError(expr, "exponent is too large")
+		}
+		pow := new(big.Rat).SetFrac(new(big.Int).Exp(l.Num(), exp, nil), new(big.Int).Exp(l.Denom(), exp, nil))
+		if r.Sign() < 0 {
+			pow.Inv(pow)
+		}
+		return pow, nil
+	case token.Greater:
+		return l.Cmp(r) > 0, nil
+	case token.GreaterEqual:
+		return l.Cmp(r) >= 0, nil
+	case token.Less:
+		return l.Cmp(r) < 0, nil
+	case token.LessEqual:
+		return l.Cmp(r) <= 0, nil
+	}
+	return nil, RuntimeTypeError{Operator: expr.Operator, Vals: []interface{}{l, r}, Trace: p.stackTrace(expr.Operator.Span)}
+}
+
+// compareOperator applies a comparison operator with exact values
+func compareOperator(op *token.Token, left Value, right Value) bool {
+	order, ok := compareNumbers(left, right)
+	if !ok {
+		return false
+	}
+	switch op.Type {
+	case token.Greater:
+		return order > 0
+	case token.GreaterEqual:
+		return order >= 0
+	case token.Less:
+		return order < 0
+	}
+	return order <= 0
+}
+
+func floatArithmetic(op *token.Token, l float64, r float64) (Value, error) {
+	switch op.Type {
+	case token.Plus:
+		return l + r, nil
+	case token.Minus:
+		return l - r, nil
+	case token.Star:
+		return l * r, nil
+	case token.Slash:
+		return l / r, nil
+	case token.Percent:
+		return math.Mod(l, r), nil
+	case token.StarStar:
+		return math.Pow(l, r), nil
+	case token.Greater:
+		return l > r, nil
+	case token.GreaterEqual:
+		return l >= r, nil
+	case token.Less:
+		return l < r, nil
+	case token.LessEqual:
+		return l <= r, nil
+	}
+	return nil, RuntimeTypeError{Operator: op, Vals: []interface{}{l, r}}
+}
+
+// bitwise applies the bitwise operator of `expr` on two integers
+func (p *Interpreter) bitwise(expr *ast.BinaryExpr, left Value, right Value) (Value, error) {
+	l, r := toBigInt(left), toBigInt(right)
+	switch expr.Operator.Type {
+	case token.Ampersand:
+		return normalizeInt(new(big.Int).And(l, r)), nil
+	case token.Pipe:
+		return normalizeInt(new(big.Int).Or(l, r)), nil
+	case token.Caret:
+		return normalizeInt(new(big.Int).Xor(l, r)), nil
+	case token.LessLess, token.GreaterGreater:
+		if r.Sign() < 0 {
+			return nil, p.emitRuntimeError(expr, "negative shift count")
+		}
+		if !r.IsUint64() || r.Uint64() > maxIntBits {
+			return nil, p.emitRuntimeError(expr, "shift count is too large")
+		}
+		if expr.Operator.Type == token.LessLess {
+			return normalizeInt(new(big.Int).Lsh(l, uint(r.Uint64()))), nil
+		}
+		return normalizeInt(new(big.Int).Rsh(l, uint(r.Uint64()))), nil
+	}
+	return nil, RuntimeTypeError{Operator: expr.Operator, Vals: []interface{}{left, right}, Trace: p.stackTrace(expr.Operator.Span)}
+}
+
+// complement computes the bitwise complement of an integer, that is -v - 1
+func complement(v Value) Value {
+	if n, ok := v.(int64); ok {
+		return ^n
+	}
+	return normalizeInt(new(big.Int).Not(v.(*big.Int)))
+}
+
+// negate computes the opposite of a number
+func negate(v Value) Value {
+	switch n := v.(type) {
+	case int64:
+		if n == math.MinInt64 {
+			return new(big.Int).Neg(big.NewInt(n))
+		}
+		return -n
+	case *big.Int:
+		return normalizeInt(new(big.Int).Neg(n))
+	case *big.Rat:
+		return new(big.Rat).Neg(n)
+	}
+	return -toFloat(v)
+}
@@ -0,0 +1,226 @@
+package interp
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// definePrelude installs the native functions that every Lox program can use
+func (p *Interpreter) definePrelude() {
+	p.DefineNative("clock", 0, nativeClock)
+	p.DefineNative("str", 1, nativeStr)
+	p.DefineNative("num", 1, nativeNum)
+	p.DefineNative("int", 1, nativeInt)
+	p.DefineNative("float", 1, nativeFloat)
+	p.DefineNative("decimal", 1, nativeDecimal)
+	p.DefineNative("len", 1, nativeLen)
+	p.DefineNative("type", 1, nativeType)
+	p.DefineNative("range", VariadicArity, nativeRange)
+	p.DefineNative("input", VariadicArity, nativeInput)
+	p.DefineNative("exit", VariadicArity, nativeExit)
+}
+
+// clock() returns the number of seconds since the Unix epoch
+func nativeClock(interpreter *Interpreter, args []Value) (Value, error) {
+	return float64(time.Now().UnixNano()) / float64(time.Second), nil
+}
+
+// str(value) converts a value to the string that "print" would display
+func nativeStr(interpreter *Interpreter, args []Value) (Value, error) {
+	return Stringify(args[0]), nil
+}
+
+// num(value) converts a string to an integer, or to a float if it is not an integer.
+// It evaluates to nil if the string is not a valid number.
+func nativeNum(interpreter *Interpreter, args []Value) (Value, error) {
+	switch v := args[0].(type) {
+	case int64, float64, *big.Int, *big.Rat:
+		return v, nil
+	case string:
+		if num, ok := new(big.Int).SetString(strings.TrimSpace(v), 10); ok {
+			return normalizeInt(num), nil
+		}
+		num, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, nil
+		}
+		return num, nil
+	}
+	return nil, &RuntimeError{Reason: fmt.Sprintf("num() cannot convert a %s", typeName(args[0]))}
+}
+
+// int(value) converts a number or a string to an integer. Floats and decimals are
+// truncated toward zero. It evaluates to nil if the string is not a valid integer.
+func nativeInt(interpreter *Interpreter, args []Value) (Value, error) {
+	switch v := args[0].(type) {
+	case int64, *big.Int:
+		return v, nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, &RuntimeError{Reason: fmt.Sprintf("int() cannot convert %s to an integer", Stringify(v))}
+		}
+		num, _ := big.NewFloat(v).Int(nil)
+		return normalizeInt(num), nil
+	case *big.Rat:
+		return normalizeInt(new(big.Int).Quo(v.Num(), v.Denom())), nil
+	case string:
+		num, ok := new(big.Int).SetString(strings.TrimSpace(v), 10)
+		if !ok {
+			return nil, nil
+		}
+		return normalizeInt(num), nil
+	}
+	return nil, &RuntimeError{Reason: fmt.Sprintf("int() cannot convert a %s", typeName(args[0]))}
+}
+
+// float(value) converts a number or a string to a float.
+// It evaluates to nil if the string is not a valid number.
+func nativeFloat(interpreter *Interpreter, args []Value) (Value, error) {
+	switch v := args[0].(type) {
+	case int64, float64, *big.Int, *big.Rat:
+		return toFloat(v), nil
+	case string:
+		num, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, nil
+		}
+		return num, nil
+	}
+	return nil, &RuntimeError{Reason: fmt.Sprintf("float() cannot convert a %s", typeName(args[0]))}
+}
+
+// decimal(value) converts a number or a string to an exact decimal.
+// It evaluates to nil if the string is not a valid number.
+func nativeDecimal(interpreter *Interpreter, args []Value) (Value, error) {
+	switch v := args[0].(type) {
+	case int64, *big.Int, *big.Rat:
+		return toDecimal(v), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, &RuntimeError{Reason: fmt.Sprintf("decimal() cannot convert %s to a decimal", Stringify(v))}
+		}
+		// Take the shortest representation of the float, so that 0.1 is exactly 1/10
+		num, _ := new(big.Rat).SetString(strconv.FormatFloat(v, 'g', -1, 64))
+		return num, nil
+	case string:
+		num, ok := new(big.Rat).SetString(strings.TrimSpace(v))
+		if !ok {
+			return nil, nil
+		}
+		return num, nil
+	}
+	return nil, &RuntimeError{Reason: fmt.Sprintf("decimal() cannot convert a %s", typeName(args[0]))}
+}
+
+// len(value) returns the number of characters in a string, or the number of elements in a list or a map
+func nativeLen(interpreter *Interpreter, args []Value) (Value, error) {
+	switch v := args[0].(type) {
+	case string:
+		return int64(utf8.RuneCountInString(v)), nil
+	case *LoxList:
+		return int64(len(v.Elements)), nil
+	case *LoxMap:
+		return int64(v.Len()), nil
+	}
+	return nil, &RuntimeError{Reason: fmt.Sprintf("len() is not defined for a %s", typeName(args[0]))}
+}
+
+// type(value) returns the name of the type of a value
+func nativeType(interpreter *Interpreter, args []Value) (Value, error) {
+	return typeName(args[0]), nil
+}
+
+// range([start,] end[, step]) returns the integers from `start` (0 by default) to `end` excluded,
+// spaced by `step` (1 by default). The integers are produced on demand by a for-in loop.
+func nativeRange(interpreter *Interpreter, args []Value) (Value, error) {
+	if len(args) < 1 || len(args) > 3 {
+		return nil, &RuntimeError{Reason: "range() expects 1 to 3 arguments"}
+	}
+	bounds := []int64{0, 0, 1}
+	for idx, arg := range args {
+		n, ok := arg.(int64)
+		if !ok {
+			return nil, &RuntimeError{Reason: fmt.Sprintf("range() expects integers, not a %s", typeName(arg))}
+		}
+		bounds[idx] = n
+	}
+	if len(args) == 1 {
+		bounds[0], bounds[1] = 0, bounds[0]
+	}
+	if bounds[2] == 0 {
+		return nil, &RuntimeError{Reason: "range() step cannot be zero"}
+	}
+	return &LoxRange{Start: bounds[0], End: bounds[1], Step: bounds[2]}, nil
+}
+
+// input([prompt]) reads a line from the standard input. It evaluates to nil
+// when the input is exhausted.
+func nativeInput(interpreter *Interpreter, args []Value) (Value, error) {
+	if len(args) > 1 {
+		return nil, &RuntimeError{Reason: "input() accepts at most one argument"}
+	}
+	if len(args) == 1 {
+		fmt.Fprint(interpreter.Stdout(), Stringify(args[0]))
+	}
+	line, err := interpreter.Stdin().ReadString('\n')
+	if err == io.EOF && line == "" {
+		return nil, nil
+	}
+	if err != nil && err != io.EOF {
+		return nil, &RuntimeError{Reason: fmt.Sprintf("input() failed: %s", err)}
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// exit([code]) stops the program with an exit code, which defaults to 0
+func nativeExit(interpreter *Interpreter, args []Value) (Value, error) {
+	if len(args) > 1 {
+		return nil, &RuntimeError{Reason: "exit() accepts at most one argument"}
+	}
+	code := 0
+	if len(args) == 1 {
+		v, ok := toInt(args[0])
+		if !ok {
+			return nil, &RuntimeError{Reason: "exit() expects an integer code"}
+		}
+		code = int(v)
+	}
+	// Return an error to unwind the call stack until reaching the host program
+	return nil, &RuntimeExit{Code: code}
+}
+
+func typeName(v Value) string {
+	switch v.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "bool"
+	case int64, *big.Int:
+		return "int"
+	case *big.Rat:
+		return "decimal"
+	case float64:
+		return "float"
+	case string:
+		return "string"
+	case *LoxList:
+		return "list"
+	case *LoxMap:
+		return "map"
+	case *LoxRange:
+		return "range"
+	case *LoxFunction, *NativeFunction:
+		return "function"
+	case *LoxClass:
+		return "class"
+	case *LoxClassInstance:
+		return "instance"
+	}
+	return "unknown"
+}
@@ -0,0 +1,108 @@
+// Package lox is the embedding entry point of gLox. It wires the scanner,
+// parser, resolver and interpreter together so that host programs can run
+// Lox source with a single call.
+package lox
+
+import (
+	"github.com/StanleyGY/Lox/gLox/interp"
+	"github.com/StanleyGY/Lox/gLox/parser"
+	"github.com/StanleyGY/Lox/gLox/resolver"
+	"github.com/StanleyGY/Lox/gLox/scanner"
+	"io"
+)
+
+type config struct {
+	interpreter *interp.Interpreter
+	args        []string
+	env         bool
+	interpOpts  []interp.Option
+}
+
+// Option customizes a single call to Run
+type Option func(*config)
+
+// WithInterpreter runs the source on an existing interpreter, so that globals
+// declared by earlier runs remain visible
+func WithInterpreter(interpreter *interp.Interpreter) Option {
+	return func(c *config) {
+		c.interpreter = interpreter
+	}
+}
+
+// WithArgs exposes `args` to the program as a list, through args()
+func WithArgs(args ...string) Option {
+	return func(c *config) {
+		c.args = args
+	}
+}
+
+// WithEnv exposes the environment variables of the process through env(name)
+func WithEnv() Option {
+	return func(c *config) {
+		c.env = true
+	}
+}
+
+// WithStdout redirects the output of "print" and the output natives
+func WithStdout(w io.Writer) Option {
+	return func(c *config) {
+		c.interpOpts = append(c.interpOpts, interp.WithStdout(w))
+	}
+}
+
+// WithStderr redirects error reports and other diagnostics
+func WithStderr(w io.Writer) Option {
+	return func(c *config) {
+		c.interpOpts = append(c.interpOpts, interp.WithStderr(w))
+	}
+}
+
+// WithStdin sets where "input()" and the input natives read from
+func WithStdin(r io.Reader) Option {
+	return func(c *config) {
+		c.interpOpts = append(c.interpOpts, interp.WithStdin(r))
+	}
+}
+
+// Run scans, parses, resolves and evaluates `src`.
+// The first phase that fails stops the run and its error is returned.
+// Lexical and syntax errors are all reported together, as a scanner.ScanningErrors
+// and a parser.ParsingErrors respectively.
+// A program stopped by "exit()" returns an *interp.RuntimeExit carrying the exit code.
+func Run(src string, opts ...Option) error {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.interpreter == nil {
+		cfg.interpreter = interp.MakeInterpreter(cfg.interpOpts...)
+	} else {
+		for _, opt := range cfg.interpOpts {
+			opt(cfg.interpreter)
+		}
+	}
+	if cfg.args != nil {
+		cfg.interpreter.DefineScriptArgs(cfg.args)
+	}
+	if cfg.env {
+		cfg.interpreter.DefineEnv()
+	}
+
+	s := &scanner.ScannerImpl{}
+	tokens, err := s.Scan(src)
+	if err != nil {
+		return err
+	}
+
+	p := &parser.RDParser{}
+	stmts, err := p.Parse(tokens)
+	if err != nil {
+		return err
+	}
+
+	r := resolver.MakeResolver(cfg.interpreter)
+	if err = r.Resolve(stmts); err != nil {
+		return err
+	}
+	return cfg.interpreter.Evaluate(stmts)
+}
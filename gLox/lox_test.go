@@ -0,0 +1,29 @@
+package lox
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("Test separate outputs", func(t *testing.T) {
+		var first, second bytes.Buffer
+
+		assert.NoError(t, Run(`print "first";`, WithStdout(&first)))
+		assert.NoError(t, Run(`print "second";`, WithStdout(&second)))
+		assert.Equal(t, "first\n", first.String())
+		assert.Equal(t, "second\n", second.String())
+	})
+
+	t.Run("Test reading input", func(t *testing.T) {
+		var out bytes.Buffer
+
+		err := Run(`var name = input("name? "); print "hi " + name;`,
+			WithStdout(&out), WithStdin(strings.NewReader("lox\n")))
+		assert.NoError(t, err)
+		assert.Equal(t, "name? hi lox\n", out.String())
+	})
+}
@@ -0,0 +1,1242 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/ast"
+	"github.com/StanleyGY/Lox/gLox/token"
+	"unicode/utf8"
+)
+
+/*
+Use right-associative notations:
+
+	program        → declaration* EOF
+
+	declaration    → classDecl | funDecl | varDecl | statement
+	classDecl      → "class" IDENTIFIER ( "<" IDENTIFIER )? "{" function* "}"
+	varDecl    	   → "var" IDENTIFIER ( "=" EXPRESSION )? ";"
+	funDecl        → "fun" function
+	function       → IDENTIFIER "(" parameters? ")" block
+	parameters     → IDENTIFIER ( "," IDENTIFIER )*
+
+	statement      → block | exprStmt | printStmt | ifStmt | forStmt | whileStmt | returnStmt | breakStmt
+				   | continueStmt | labeledStmt
+	labeledStmt    → IDENTIFIER ":" ( forStmt | whileStmt )
+	block 		   → "{" declaration* "}"
+	exprStmt       → expression ";"
+	printStmt      → "print" expression ";"
+	ifStmt		   → "if" "(" expression ")" statement ( "else" statement )?
+	forStmt        → "for" "(" ( varDecl | exprStmt | ";" ) | expression? ";" expression? ")" statement
+				   | "for" "(" "var" IDENTIFIER "in" expression ")" statement
+	whileStmt      → "while" "(" expression ")" statement
+	returnStmt     → "return" expression? ";"
+	breakStmt      → "break" IDENTIFIER? ";"
+	continueStmt   → "continue" IDENTIFIER? ";"
+
+	expression     → assignment
+	assignment     → ( call "." IDENTIFIER | call "[" expression "]" | IDENTIFIER ) ( "=" | "+=" | "-=" | "*=" | "/=" | "%=" ) assignment
+				   | logic_or
+	logic_or	   → logic_and ( "or" logic_and )*
+	logic_and      → equality ( "and" equality )*
+	equality       → comparison (( "!=" | "==" ) comparison )*
+	comparison     → bit_or (( ">" | ">=" | "<" | "<=" ) bit_or )*
+	bit_or         → bit_xor ( "|" bit_xor )*
+	bit_xor        → bit_and ( "^" bit_and )*
+	bit_and        → shift ( "&" shift )*
+	shift          → term (( "<<" | ">>" ) term )*
+	term           → factor (( "-" | "+" ) factor )*
+	factor         → unary (( "/" | "*" | "%" ) unary )*
+	unary          → (( "!" | "-" | "~" ) unary) | ( "++" | "--" ) unary | power
+	power          → postfix ( "**" unary )?
+	postfix        → call ( "++" | "--" )?
+	call           → primary ( "(" arguments? ")" | "." IDENTIFIER | "[" index "]" )*
+	index          → expression | expression? ":" expression?
+	arguments      → expression ( "," expression )*
+	primary        → NUMBER | STRING | "true" | "false" | "nil" | "(" expression ")" | IDENTIFIER | "super" "." IDENTIFIER
+				   | interpolation | list | map
+	list           → "[" ( expression ( "," expression )* ","? )? "]"
+	map            → "{" ( entry ( "," entry )* ","? )? "}"
+	entry          → expression ":" expression
+
+	A "{" starts a block in statement position, and a map in expression position.
+	interpolation  → INTERPOLATION expression ( INTERPOLATION_MIDDLE expression )* INTERPOLATION_END
+*/
+
+const (
+	MaxNumFunCallArguments = 255
+)
+
+// Parser builds the statements of a program. Syntax errors are returned together as a ParsingErrors.
+type Parser interface {
+	Parse(tokens []*token.Token) ([]ast.Stmt, error)
+}
+
+var _ Parser = &RDParser{}
+
+type RDParser struct {
+	tokens  []*token.Token
+	currIdx int
+	errors  ParsingErrors
+	// Number of blocks around the current statement
+	blockDepth int
+}
+
+type ParsingError struct {
+	Reason   string
+	Span     token.Span
+	TokenIdx int
+	Tokens   []*token.Token
+}
+
+func (e ParsingError) Error() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Parsing failed around line %d, column %d: \n", e.Span.Start.Line, e.Span.Start.Column))
+	for i := max(0, e.TokenIdx-10); i <= min(e.TokenIdx+5, len(e.Tokens)-1); i++ {
+		buf.WriteString(e.Tokens[i].Lexeme)
+		buf.WriteString(" ")
+	}
+	buf.WriteString("\n")
+	for i := max(0, e.TokenIdx-10); i <= min(e.TokenIdx+5, len(e.Tokens)-1); i++ {
+		width := utf8.RuneCountInString(e.Tokens[i].Lexeme)
+		for j := 0; j < width; j++ {
+			if i == e.TokenIdx && j <= width/2 {
+				buf.WriteString("^")
+			} else {
+				buf.WriteString(" ")
+			}
+		}
+		buf.WriteString(" ")
+	}
+	buf.WriteString("\nFailed reason: ")
+	buf.WriteString(e.Reason)
+	return buf.String()
+}
+
+// ParsingErrors lists all syntax errors found in a program
+type ParsingErrors []*ParsingError
+
+func (e ParsingErrors) Error() string {
+	var buf bytes.Buffer
+	for idx, err := range e {
+		if idx > 0 {
+			buf.WriteString("\n\n")
+		}
+		buf.WriteString(err.Error())
+	}
+	return buf.String()
+}
+
+// Parse builds the statements of a program. On syntax errors, it still returns
+// the statements that are parsed successfully, along with a ParsingErrors.
+func (p *RDParser) Parse(tokens []*token.Token) ([]ast.Stmt, error) {
+	var stmts []ast.Stmt
+
+	p.tokens = tokens
+	p.currIdx = 0
+	p.errors = nil
+	p.blockDepth = 0
+
+	for p.currIdx < len(p.tokens) {
+		if p.match(token.EOF) {
+			// TODO: what's the use for EOF?
+			break
+		}
+		if stmt := p.declarationOrSync(); stmt != nil {
+			stmts = append(stmts, stmt)
+		}
+	}
+	if len(p.errors) > 0 {
+		return stmts, p.errors
+	}
+	return stmts, nil
+}
+
+func (p *RDParser) peek() *token.Token {
+	if p.currIdx >= len(p.tokens) {
+		return nil
+	}
+	return p.tokens[p.currIdx]
+}
+
+func (p *RDParser) previous() *token.Token {
+	if p.currIdx == 0 {
+		return nil
+	}
+	return p.tokens[p.currIdx-1]
+}
+
+func (p *RDParser) match(tokenType int) bool {
+	token := p.peek()
+	return token != nil && token.Type == tokenType
+}
+
+func (p *RDParser) advance() {
+	p.currIdx += 1
+}
+
+func (p *RDParser) hasNext() bool {
+	return p.currIdx < len(p.tokens)
+}
+
+func (p *RDParser) emitParsingError(reason string) error {
+	var span token.Span
+	if t := p.peek(); t != nil {
+		span = t.Span
+	} else if t = p.previous(); t != nil {
+		span = token.Span{Start: t.Span.End, End: t.Span.End}
+	}
+	return &ParsingError{Reason: reason, Span: span, TokenIdx: p.currIdx, Tokens: p.tokens}
+}
+
+// spannable is implemented by every AST node through ast.SrcRange
+type spannable interface {
+	SetSpan(span token.Span)
+}
+
+// setSpan records the source range of `node` and returns the node
+func setSpan[N spannable](node N, span token.Span) N {
+	node.SetSpan(span)
+	return node
+}
+
+// spanFrom returns the source range from `start` to the last consumed token
+func (p *RDParser) spanFrom(start *token.Token) token.Span {
+	end := p.previous()
+	if start == nil || end == nil {
+		return token.Span{}
+	}
+	return token.Span{Start: start.Span.Start, End: end.Span.End}
+}
+
+// joinSpans returns the source range covering both nodes
+func joinSpans(first ast.Node, last ast.Node) token.Span {
+	return first.Span().Join(last.Span())
+}
+
+// advanceIfMatch checks if current token matches any of `tokenTypes`.
+// If yes, return the current token, and advance to the next token
+func (p *RDParser) advanceIfMatch(tokenTypes ...int) bool {
+	for _, t := range tokenTypes {
+		if p.match(t) {
+			p.advance()
+			return true
+		}
+	}
+	return false
+}
+
+// declarationOrSync parses a declaration. On a syntax error, the error is recorded
+// and the parser skips to the next statement boundary, so that the rest of
+// the program is still checked. A nil statement is returned in that case.
+func (p *RDParser) declarationOrSync() ast.Stmt {
+	start := p.currIdx
+	stmt, err := p.declaration()
+	if err != nil {
+		p.errors = append(p.errors, err.(*ParsingError))
+		p.synchronize(start)
+		return nil
+	}
+	return stmt
+}
+
+// synchronize discards tokens until a statement boundary: after a ";",
+// or before a keyword that starts a statement, or before a "}" that closes a block.
+// A "}" outside of any block is discarded too. The failing token is discarded only if
+// it's not a boundary, or if the failed statement began at `start` with it.
+func (p *RDParser) synchronize(start int) {
+	if p.hasNext() && !p.match(token.EOF) && (p.currIdx == start || !p.atBoundary()) {
+		p.advance()
+	}
+	for p.hasNext() && !p.match(token.EOF) {
+		if p.previous().Type == token.SemiColon || p.atBoundary() {
+			return
+		}
+		p.advance()
+	}
+}
+
+// atBoundary checks whether the next token begins a statement or closes a block
+func (p *RDParser) atBoundary() bool {
+	if !p.hasNext() || p.match(token.EOF) {
+		return true
+	}
+	switch p.peek().Type {
+	case token.Class, token.Fun, token.Var, token.For, token.If,
+		token.While, token.Print, token.Return:
+		return true
+	case token.RightBrace:
+		return p.blockDepth > 0
+	}
+	return false
+}
+
+func (p *RDParser) declaration() (ast.Stmt, error) {
+	if p.match(token.Var) {
+		return p.varDecl()
+	}
+	if p.match(token.Fun) {
+		return p.funDecl()
+	}
+	if p.match(token.Class) {
+		return p.classDecl()
+	}
+	return p.statement()
+}
+
+func (p *RDParser) varDecl() (ast.Stmt, error) {
+	var initializer ast.Expr
+	var name *token.Token
+	var err error
+
+	start := p.peek()
+	if !p.advanceIfMatch(token.Var) {
+		return nil, p.emitParsingError("variable declaration missing \"var\" keyword")
+	}
+	if !p.advanceIfMatch(token.Identifier) {
+		return nil, p.emitParsingError("variable declaration missing identifier")
+	}
+	name = p.previous()
+
+	if p.advanceIfMatch(token.Equal) {
+		if initializer, err = p.expression(); err != nil {
+			return nil, err
+		}
+	}
+	if !p.advanceIfMatch(token.SemiColon) {
+		return nil, p.emitParsingError("missing \";\"")
+	}
+	return setSpan(&ast.VarDeclStmt{Name: name, Initializer: initializer, Doc: start.Doc}, p.spanFrom(start)), nil
+}
+
+func (p *RDParser) funDecl() (ast.Stmt, error) {
+	start := p.peek()
+	if !p.advanceIfMatch(token.Fun) {
+		return nil, p.emitParsingError("func declaration missing \"fun\" keyword")
+	}
+	stmt, err := p.function()
+	if err != nil {
+		return nil, err
+	}
+	// Include the "fun" keyword in the source range and take its doc comments
+	stmt.Doc = start.Doc
+	return setSpan(stmt, p.spanFrom(start)), nil
+}
+
+func (p *RDParser) function() (*ast.FuncDeclStmt, error) {
+	var name *token.Token
+	var parameters []*token.Token
+	var body ast.Stmt
+	var err error
+
+	start := p.peek()
+
+	// Match function signatures
+	if !p.advanceIfMatch(token.Identifier) {
+		return nil, p.emitParsingError("func declaration missing name")
+	}
+	name = p.previous()
+
+	if !p.advanceIfMatch(token.LeftParen) {
+		return nil, p.emitParsingError("func declaration missing \"(\"")
+	}
+	if parameters, err = p.parameters(); err != nil {
+		return nil, err
+	}
+	if len(parameters) > MaxNumFunCallArguments {
+		return nil, p.emitParsingError("func declaration argument list too long")
+	}
+	if !p.advanceIfMatch(token.RightParen) {
+		return nil, p.emitParsingError("func declaration missing \")\"")
+	}
+
+	// Match function implementation
+	if body, err = p.blockStmt(); err != nil {
+		return nil, err
+	}
+	return setSpan(&ast.FuncDeclStmt{Name: name, Params: parameters, Body: body, Doc: start.Doc}, p.spanFrom(start)), nil
+}
+
+func (p *RDParser) classDecl() (ast.Stmt, error) {
+	var name *token.Token
+	var superClass *ast.VariableExpr
+	var methods []*ast.FuncDeclStmt
+	var err error
+
+	start := p.peek()
+	if !p.advanceIfMatch(token.Class) {
+		return nil, p.emitParsingError("class declaration missing \"class\" keyword")
+	}
+	if !p.advanceIfMatch(token.Identifier) {
+		return nil, p.emitParsingError("class declaration missing name")
+	}
+	name = p.previous()
+
+	if p.advanceIfMatch(token.Less) {
+		if !p.advanceIfMatch(token.Identifier) {
+			return nil, p.emitParsingError("class declaration missing super class")
+		}
+		// Wrap this additionally in an expr so semantic analysis
+		// can be done on this identifier
+		superClass = setSpan(&ast.VariableExpr{Name: p.previous()}, p.previous().Span)
+	}
+	if !p.advanceIfMatch(token.LeftBrace) {
+		return nil, p.emitParsingError("class declaration missing \"{\"")
+	}
+	for !p.advanceIfMatch(token.RightBrace) {
+		var m *ast.FuncDeclStmt
+		if m, err = p.function(); err != nil {
+			return nil, err
+		}
+		methods = append(methods, m)
+	}
+	return setSpan(&ast.ClassDeclStmt{Name: name, SuperClass: superClass, Methods: methods, Doc: start.Doc}, p.spanFrom(start)), nil
+}
+
+func (p *RDParser) parameters() ([]*token.Token, error) {
+	var params []*token.Token
+
+	// Function with no parameters
+	if !p.match(token.Identifier) {
+		return params, nil
+	}
+	for {
+		if !p.advanceIfMatch(token.Identifier) {
+			return nil, p.emitParsingError("missing func parameter after \",\"")
+		}
+		params = append(params, p.previous())
+		if !p.advanceIfMatch(token.Comma) {
+			return params, nil
+		}
+	}
+}
+
+func (p *RDParser) statement() (ast.Stmt, error) {
+	if p.match(token.Print) {
+		return p.printStmt()
+	}
+	if p.match(token.LeftBrace) {
+		return p.blockStmt()
+	}
+	if p.match(token.If) {
+		return p.ifStmt()
+	}
+	if p.match(token.While) {
+		return p.whileStmt(nil)
+	}
+	if p.match(token.For) {
+		return p.forStmt(nil)
+	}
+	if p.isLabel() {
+		return p.labeledStmt()
+	}
+	if p.match(token.Return) {
+		return p.returnStmt()
+	}
+	if p.match(token.Break) {
+		return p.breakStmt()
+	}
+	if p.match(token.Continue) {
+		return p.continueStmt()
+	}
+	return p.expressionStmt()
+}
+
+func (p *RDParser) printStmt() (ast.Stmt, error) {
+	var expr ast.Expr
+	var err error
+
+	start := p.peek()
+	if !p.advanceIfMatch(token.Print) {
+		return nil, p.emitParsingError("missing \"print\" keyword")
+	}
+	if expr, err = p.expression(); err != nil {
+		return nil, err
+	}
+	if !p.advanceIfMatch(token.SemiColon) {
+		return nil, p.emitParsingError("missing \";\"")
+	}
+	return setSpan(&ast.PrintStmt{Child: expr}, p.spanFrom(start)), nil
+}
+
+func (p *RDParser) expressionStmt() (ast.Stmt, error) {
+	var expr ast.Expr
+	var err error
+
+	start := p.peek()
+	if expr, err = p.expression(); err != nil {
+		return nil, err
+	}
+	if !p.advanceIfMatch(token.SemiColon) {
+		return nil, p.emitParsingError("missing \";\"")
+	}
+	return setSpan(&ast.InlineExprStmt{Child: expr}, p.spanFrom(start)), nil
+}
+
+func (p *RDParser) blockStmt() (ast.Stmt, error) {
+	var stmts []ast.Stmt
+
+	start := p.peek()
+	if !p.advanceIfMatch(token.LeftBrace) {
+		return nil, p.emitParsingError("missing \"{\"")
+	}
+	p.blockDepth++
+	defer func() { p.blockDepth-- }()
+	for p.hasNext() && !p.match(token.RightBrace) && !p.match(token.EOF) {
+		if stmt := p.declarationOrSync(); stmt != nil {
+			stmts = append(stmts, stmt)
+		}
+	}
+	if !p.advanceIfMatch(token.RightBrace) {
+		return nil, p.emitParsingError("missing \"}\"")
+	}
+	return setSpan(&ast.BlockStmt{Stmts: stmts}, p.spanFrom(start)), nil
+}
+
+func (p *RDParser) ifStmt() (ast.Stmt, error) {
+	var condition ast.Expr
+	var thenBranch ast.Stmt
+	var elseBranch ast.Stmt
+	var err error
+
+	start := p.peek()
+	if !p.advanceIfMatch(token.If) {
+		return nil, p.emitParsingError("missing \"if\" keyword")
+	}
+	if !p.advanceIfMatch(token.LeftParen) {
+		return nil, p.emitParsingError("if condition missing \"{\"")
+	}
+	if condition, err = p.expression(); err != nil {
+		return nil, err
+	}
+	if !p.advanceIfMatch(token.RightParen) {
+		return nil, p.emitParsingError("if condition missing \"}\"")
+	}
+	if thenBranch, err = p.statement(); err != nil {
+		return nil, err
+	}
+
+	// In case of the "dangling else" problem (i.e. if A if B else C), the "else" statement
+	// is bounded to the nearest "if" statement
+	if p.advanceIfMatch(token.Else) {
+		if elseBranch, err = p.statement(); err != nil {
+			return nil, err
+		}
+	}
+	return setSpan(&ast.IfStmt{Condition: condition, ThenBranch: thenBranch, ElseBranch: elseBranch}, p.spanFrom(start)), nil
+}
+
+// isLabel looks ahead for the IDENTIFIER ":" label of a loop
+func (p *RDParser) isLabel() bool {
+	if p.currIdx+1 >= len(p.tokens) {
+		return false
+	}
+	return p.tokens[p.currIdx].Type == token.Identifier && p.tokens[p.currIdx+1].Type == token.Colon
+}
+
+func (p *RDParser) labeledStmt() (ast.Stmt, error) {
+	// Skip ":", which was checked by isLabel()
+	label := p.peek()
+	p.advance()
+	p.advance()
+	if p.match(token.While) {
+		return p.whileStmt(label)
+	}
+	if p.match(token.For) {
+		return p.forStmt(label)
+	}
+	return nil, p.emitParsingError(fmt.Sprintf("label %s must be followed by a loop", label.Lexeme))
+}
+
+// whileStmt parses a while loop, with an optional `label`
+func (p *RDParser) whileStmt(label *token.Token) (ast.Stmt, error) {
+	var condition ast.Expr
+	var body ast.Stmt
+	var err error
+
+	start := p.peek()
+	if !p.advanceIfMatch(token.While) {
+		return nil, p.emitParsingError("missing \"while\" keyword")
+	}
+	if !p.advanceIfMatch(token.LeftParen) {
+		return nil, p.emitParsingError("while loop missing \"{\"")
+	}
+	if condition, err = p.expression(); err != nil {
+		return nil, err
+	}
+	if !p.advanceIfMatch(token.RightParen) {
+		return nil, p.emitParsingError("while loop missing \"}\"")
+	}
+	if body, err = p.statement(); err != nil {
+		return nil, err
+	}
+	return setSpan(&ast.WhileStmt{Label: label, Condition: condition, Body: body}, p.spanFrom(start)), nil
+}
+
+// forStmt parses a C-style or a for-in loop, with an optional `label`
+func (p *RDParser) forStmt(label *token.Token) (ast.Stmt, error) {
+	var initializer ast.Stmt
+	var condition ast.Expr
+	var increment ast.Expr
+	var body ast.Stmt
+	var err error
+
+	start := p.peek()
+	if !p.advanceIfMatch(token.For) {
+		return nil, p.emitParsingError("missing \"for\" keyword")
+	}
+	if !p.advanceIfMatch(token.LeftParen) {
+		return nil, p.emitParsingError("for loop missing \"(\"")
+	}
+	if p.isForIn() {
+		return p.forInStmt(start, label)
+	}
+	// Initializer clause
+	if p.match(token.Var) {
+		if initializer, err = p.varDecl(); err != nil {
+			return nil, err
+		}
+	} else if !p.advanceIfMatch(token.SemiColon) {
+		if initializer, err = p.expressionStmt(); err != nil {
+			return nil, err
+		}
+	}
+	// Condition clause
+	if !p.advanceIfMatch(token.SemiColon) {
+		if condition, err = p.expression(); err != nil {
+			return nil, err
+		}
+		if !p.advanceIfMatch(token.SemiColon) {
+			return nil, p.emitParsingError("for loop condition clause missing \"'\"")
+		}
+	}
+	// Increment clause
+	if !p.advanceIfMatch(token.RightParen) {
+		if increment, err = p.expression(); err != nil {
+			return nil, err
+		}
+		if !p.advanceIfMatch(token.RightParen) {
+			return nil, p.emitParsingError("for loop condition clause missing \")\"")
+		}
+	}
+	// Body
+	if body, err = p.statement(); err != nil {
+		return nil, err
+	}
+
+	// C-style for-loop is just a syntactic sugar of a while-loop
+	// If condition is omitted, then it's default to true
+	// The synthesized nodes take the source range of the clauses they are made of.
+	// The increment stays out of the body, so that "continue" doesn't skip it.
+	if condition == nil {
+		condition = setSpan(&ast.LiteralExpr{Value: true}, start.Span)
+	}
+
+	whileStmt := setSpan(&ast.WhileStmt{Label: label, Condition: condition, Body: body, Increment: increment}, p.spanFrom(start))
+
+	if initializer != nil {
+		return setSpan(&ast.BlockStmt{Stmts: []ast.Stmt{initializer, whileStmt}}, p.spanFrom(start)), nil
+	}
+	return whileStmt, nil
+}
+
+// isForIn looks ahead for the "var" IDENTIFIER "in" header of a for-in loop
+func (p *RDParser) isForIn() bool {
+	if p.currIdx+2 >= len(p.tokens) {
+		return false
+	}
+	return p.tokens[p.currIdx].Type == token.Var &&
+		p.tokens[p.currIdx+1].Type == token.Identifier &&
+		p.tokens[p.currIdx+2].Type == token.In
+}
+
+// forInStmt parses a for-in loop after its "(", `start` being the "for" keyword
+func (p *RDParser) forInStmt(start *token.Token, label *token.Token) (ast.Stmt, error) {
+	var iterable ast.Expr
+	var body ast.Stmt
+	var err error
+
+	// Skip "var" and "in", which were checked by isForIn()
+	p.advance()
+	name := p.peek()
+	p.advance()
+	p.advance()
+	if iterable, err = p.expression(); err != nil {
+		return nil, err
+	}
+	if !p.advanceIfMatch(token.RightParen) {
+		return nil, p.emitParsingError("for loop missing \")\"")
+	}
+	if body, err = p.statement(); err != nil {
+		return nil, err
+	}
+	return setSpan(&ast.ForInStmt{Label: label, Name: name, Iterable: iterable, Body: body}, p.spanFrom(start)), nil
+}
+
+func (p *RDParser) returnStmt() (ast.Stmt, error) {
+	var expr ast.Expr
+	var err error
+
+	start := p.peek()
+	if !p.advanceIfMatch(token.Return) {
+		return nil, p.emitParsingError("missing \"return\" keyword")
+	}
+	if !p.advanceIfMatch(token.SemiColon) {
+		if expr, err = p.expression(); err != nil {
+			return nil, err
+		}
+		if !p.advanceIfMatch(token.SemiColon) {
+			return nil, p.emitParsingError("return statement missing \";\"")
+		}
+		return setSpan(&ast.ReturnStmt{Value: expr}, p.spanFrom(start)), nil
+	}
+	return setSpan(&ast.ReturnStmt{}, p.spanFrom(start)), nil
+}
+
+func (p *RDParser) breakStmt() (ast.Stmt, error) {
+	start := p.peek()
+	if !p.advanceIfMatch(token.Break) {
+		return nil, p.emitParsingError("missing \"break\" keyword")
+	}
+	var label *token.Token
+	if p.advanceIfMatch(token.Identifier) {
+		label = p.previous()
+	}
+	if !p.advanceIfMatch(token.SemiColon) {
+		return nil, p.emitParsingError("break statement missing \";\"")
+	}
+	return setSpan(&ast.BreakStmt{Label: label}, p.spanFrom(start)), nil
+}
+
+func (p *RDParser) continueStmt() (ast.Stmt, error) {
+	start := p.peek()
+	if !p.advanceIfMatch(token.Continue) {
+		return nil, p.emitParsingError("missing \"continue\" keyword")
+	}
+	var label *token.Token
+	if p.advanceIfMatch(token.Identifier) {
+		label = p.previous()
+	}
+	if !p.advanceIfMatch(token.SemiColon) {
+		return nil, p.emitParsingError("continue statement missing \";\"")
+	}
+	return setSpan(&ast.ContinueStmt{Label: label}, p.spanFrom(start)), nil
+}
+
+func (p *RDParser) expression() (ast.Expr, error) {
+	return p.assignment()
+}
+
+func (p *RDParser) assignment() (ast.Expr, error) {
+	var left ast.Expr
+	var err error
+
+	// If an "assignment" rule is satisfied by an assignment expression,
+	// The LHS of this expr is a l-value expr that evaluates to the storage location.
+	// The RHS of this expr is a r-value expr that evaluates to a value.
+	// A l-value expr happens to satisfy "logicOr" rule, so we can use "logicOr" rule to parse it
+	// and filter out the well-defined variants.
+	if left, err = p.logicOr(); err != nil {
+		return nil, err
+	}
+
+	// Actually does assignment
+	if p.advanceIfMatch(token.Equal) {
+		var value ast.Expr
+
+		// This expr should start with an identifier followed by "logicOr" expr
+		if value, err = p.assignment(); err != nil {
+			return nil, err
+		}
+
+		switch left := left.(type) {
+		case *ast.VariableExpr:
+			return setSpan(&ast.AssignExpr{Name: left.Name, Value: value}, joinSpans(left, value)), nil
+		case *ast.GetPropertyExpr:
+			return setSpan(&ast.SetPropertyExpr{
+				Object:   left.Object,
+				Property: left.Property,
+				Value:    value,
+			}, joinSpans(left, value)), nil
+		case *ast.IndexGetExpr:
+			return setSpan(&ast.IndexSetExpr{
+				Object: left.Object,
+				Index:  left.Index,
+				Value:  value,
+			}, joinSpans(left, value)), nil
+		default:
+			return nil, p.emitParsingError("invalid assignment target")
+		}
+	}
+
+	// Compound assignment
+	if p.advanceIfMatch(token.PlusEqual, token.MinusEqual, token.StarEqual, token.SlashEqual, token.PercentEqual) {
+		var value ast.Expr
+
+		op := p.previous()
+		if !isAssignable(left) {
+			return nil, p.emitParsingError("invalid assignment target")
+		}
+		if value, err = p.assignment(); err != nil {
+			return nil, err
+		}
+		return setSpan(&ast.UpdateExpr{Target: left, Operator: binaryOperator(op), Value: value}, joinSpans(left, value)), nil
+	}
+	return left, nil
+}
+
+func isAssignable(expr ast.Expr) bool {
+	switch expr.(type) {
+	case *ast.VariableExpr, *ast.GetPropertyExpr, *ast.IndexGetExpr:
+		return true
+	}
+	return false
+}
+
+// binaryOperator converts an update operator like "+=" or "++" to the binary operator it applies.
+// The lexeme is kept for error messages.
+func binaryOperator(op *token.Token) *token.Token {
+	binaryTypes := map[int]int{
+		token.PlusEqual:    token.Plus,
+		token.MinusEqual:   token.Minus,
+		token.StarEqual:    token.Star,
+		token.SlashEqual:   token.Slash,
+		token.PercentEqual: token.Percent,
+		token.PlusPlus:     token.Plus,
+		token.MinusMinus:   token.Minus,
+	}
+	return &token.Token{Type: binaryTypes[op.Type], Lexeme: op.Lexeme, Span: op.Span}
+}
+
+// incrementUpdate builds the update of `target` by a "++" or "--" operator
+func incrementUpdate(target ast.Expr, op *token.Token, postfix bool) *ast.UpdateExpr {
+	return &ast.UpdateExpr{
+		Target:   target,
+		Operator: binaryOperator(op),
+		Value:    setSpan(&ast.LiteralExpr{Value: int64(1)}, op.Span),
+		Postfix:  postfix,
+	}
+}
+
+func (p *RDParser) logicOr() (ast.Expr, error) {
+	var left ast.Expr
+	var right ast.Expr
+	var err error
+
+	if left, err = p.logicAnd(); err != nil {
+		return nil, err
+	}
+	for p.advanceIfMatch(token.Or) {
+		op := p.previous()
+		if right, err = p.logicAnd(); err != nil {
+			return nil, err
+		}
+		left = setSpan(&ast.LogicExpr{Operator: op, Left: left, Right: right}, joinSpans(left, right))
+	}
+	return left, nil
+}
+
+func (p *RDParser) logicAnd() (ast.Expr, error) {
+	var left ast.Expr
+	var right ast.Expr
+	var err error
+
+	if left, err = p.equality(); err != nil {
+		return nil, err
+	}
+	for p.advanceIfMatch(token.And) {
+		op := p.previous()
+		if right, err = p.equality(); err != nil {
+			return nil, err
+		}
+		left = setSpan(&ast.LogicExpr{Operator: op, Left: left, Right: right}, joinSpans(left, right))
+	}
+	return left, nil
+}
+
+func (p *RDParser) equality() (ast.Expr, error) {
+	var left ast.Expr
+	var right ast.Expr
+	var err error
+
+	if left, err = p.comparison(); err != nil {
+		return nil, err
+	}
+	for p.advanceIfMatch(token.BangEqual, token.EqualEqual) {
+		op := p.previous()
+		if right, err = p.comparison(); err != nil {
+			return nil, err
+		}
+		left = setSpan(&ast.BinaryExpr{Operator: op, Left: left, Right: right}, joinSpans(left, right))
+	}
+	return left, nil
+}
+
+func (p *RDParser) comparison() (ast.Expr, error) {
+	var left ast.Expr
+	var right ast.Expr
+	var err error
+
+	if left, err = p.bitwiseOr(); err != nil {
+		return nil, err
+	}
+	for p.advanceIfMatch(token.Greater, token.GreaterEqual, token.Less, token.LessEqual) {
+		op := p.previous()
+		if right, err = p.bitwiseOr(); err != nil {
+			return nil, err
+		}
+		left = setSpan(&ast.BinaryExpr{Operator: op, Left: left, Right: right}, joinSpans(left, right))
+	}
+	return left, nil
+}
+
+func (p *RDParser) bitwiseOr() (ast.Expr, error) {
+	return p.binary(p.bitwiseXor, token.Pipe)
+}
+
+func (p *RDParser) bitwiseXor() (ast.Expr, error) {
+	return p.binary(p.bitwiseAnd, token.Caret)
+}
+
+func (p *RDParser) bitwiseAnd() (ast.Expr, error) {
+	return p.binary(p.shift, token.Ampersand)
+}
+
+func (p *RDParser) shift() (ast.Expr, error) {
+	return p.binary(p.term, token.LessLess, token.GreaterGreater)
+}
+
+// binary parses a left-associative level of binary operators, whose operands are parsed by `operand`
+func (p *RDParser) binary(operand func() (ast.Expr, error), operators ...int) (ast.Expr, error) {
+	var left ast.Expr
+	var right ast.Expr
+	var err error
+
+	if left, err = operand(); err != nil {
+		return nil, err
+	}
+	for p.advanceIfMatch(operators...) {
+		op := p.previous()
+		if right, err = operand(); err != nil {
+			return nil, err
+		}
+		left = setSpan(&ast.BinaryExpr{Operator: op, Left: left, Right: right}, joinSpans(left, right))
+	}
+	return left, nil
+}
+
+func (p *RDParser) term() (ast.Expr, error) {
+	var left ast.Expr
+	var right ast.Expr
+	var err error
+
+	if left, err = p.factor(); err != nil {
+		return nil, err
+	}
+	for p.advanceIfMatch(token.Plus, token.Minus) {
+		op := p.previous()
+		if right, err = p.factor(); err != nil {
+			return nil, err
+		}
+		left = setSpan(&ast.BinaryExpr{Operator: op, Left: left, Right: right}, joinSpans(left, right))
+	}
+	return left, nil
+}
+
+func (p *RDParser) factor() (ast.Expr, error) {
+	var left ast.Expr
+	var right ast.Expr
+	var err error
+
+	if left, err = p.unary(); err != nil {
+		return nil, err
+	}
+	for p.advanceIfMatch(token.Slash, token.Star, token.Percent) {
+		op := p.previous()
+		if right, err = p.unary(); err != nil {
+			return nil, err
+		}
+		left = setSpan(&ast.BinaryExpr{Operator: op, Left: left, Right: right}, joinSpans(left, right))
+	}
+	return left, nil
+}
+
+func (p *RDParser) unary() (ast.Expr, error) {
+	var right ast.Expr
+	var err error
+
+	if p.advanceIfMatch(token.Bang, token.Minus, token.Tilde) {
+		op := p.previous()
+		if right, err = p.unary(); err != nil {
+			return nil, err
+		}
+		return setSpan(&ast.UnaryExpr{Operator: op, Right: right}, op.Span.Join(right.Span())), nil
+	}
+	if p.advanceIfMatch(token.PlusPlus, token.MinusMinus) {
+		op := p.previous()
+		if right, err = p.unary(); err != nil {
+			return nil, err
+		}
+		if !isAssignable(right) {
+			return nil, p.emitParsingError(fmt.Sprintf("invalid operand for \"%s\"", op.Lexeme))
+		}
+		return setSpan(incrementUpdate(right, op, false), op.Span.Join(right.Span())), nil
+	}
+	return p.power()
+}
+
+// power is right-associative and binds tighter than a unary operator on its left,
+// so -2 ** 2 is -(2 ** 2) and 2 ** -1 is 2 ** (-1)
+func (p *RDParser) power() (ast.Expr, error) {
+	var left ast.Expr
+	var right ast.Expr
+	var err error
+
+	if left, err = p.postfix(); err != nil {
+		return nil, err
+	}
+	if p.advanceIfMatch(token.StarStar) {
+		op := p.previous()
+		if right, err = p.unary(); err != nil {
+			return nil, err
+		}
+		left = setSpan(&ast.BinaryExpr{Operator: op, Left: left, Right: right}, joinSpans(left, right))
+	}
+	return left, nil
+}
+
+func (p *RDParser) postfix() (ast.Expr, error) {
+	var left ast.Expr
+	var err error
+
+	if left, err = p.call(); err != nil {
+		return nil, err
+	}
+	if p.advanceIfMatch(token.PlusPlus, token.MinusMinus) {
+		op := p.previous()
+		if !isAssignable(left) {
+			return nil, p.emitParsingError(fmt.Sprintf("invalid operand for \"%s\"", op.Lexeme))
+		}
+		left = setSpan(incrementUpdate(left, op, true), left.Span().Join(op.Span))
+	}
+	return left, nil
+}
+
+func (p *RDParser) call() (ast.Expr, error) {
+	var expr ast.Expr
+	var err error
+
+	if expr, err = p.primary(); err != nil {
+		return nil, err
+	}
+
+	for {
+		if p.advanceIfMatch(token.Dot) {
+			// Handle class property-get call
+			if !p.advanceIfMatch(token.Identifier) {
+				return nil, p.emitParsingError("missing identifier for property access")
+			}
+			expr = setSpan(&ast.GetPropertyExpr{Object: expr, Property: p.previous()}, expr.Span().Join(p.previous().Span))
+
+		} else if p.advanceIfMatch(token.LeftParen) {
+			// Handle regular function call
+			if p.advanceIfMatch(token.RightParen) {
+				expr = setSpan(&ast.CallExpr{Callee: expr}, expr.Span().Join(p.previous().Span))
+			} else {
+				var arguments []ast.Expr
+				if arguments, err = p.arguments(); err != nil {
+					return nil, err
+				}
+				if len(arguments) >= MaxNumFunCallArguments {
+					return nil, p.emitParsingError("func call argument list too long")
+				}
+				if !p.advanceIfMatch(token.RightParen) {
+					return nil, p.emitParsingError("func call argument list missing \")\"")
+				}
+				expr = setSpan(&ast.CallExpr{Callee: expr, Arguments: arguments}, expr.Span().Join(p.previous().Span))
+			}
+		} else if p.advanceIfMatch(token.LeftBracket) {
+			// Handle indexing and slicing
+			if expr, err = p.index(expr); err != nil {
+				return nil, err
+			}
+		} else {
+			break
+		}
+	}
+	return expr, nil
+}
+
+// index parses an index or a slice of `object`, after its "["
+func (p *RDParser) index(object ast.Expr) (ast.Expr, error) {
+	var start ast.Expr
+	var end ast.Expr
+	var err error
+
+	if !p.match(token.Colon) {
+		if start, err = p.expression(); err != nil {
+			return nil, err
+		}
+		if p.advanceIfMatch(token.RightBracket) {
+			return setSpan(&ast.IndexGetExpr{Object: object, Index: start}, object.Span().Join(p.previous().Span)), nil
+		}
+	}
+	if !p.advanceIfMatch(token.Colon) {
+		return nil, p.emitParsingError("index missing \"]\"")
+	}
+	if !p.match(token.RightBracket) {
+		if end, err = p.expression(); err != nil {
+			return nil, err
+		}
+	}
+	if !p.advanceIfMatch(token.RightBracket) {
+		return nil, p.emitParsingError("slice missing \"]\"")
+	}
+	return setSpan(&ast.SliceExpr{Object: object, Start: start, End: end}, object.Span().Join(p.previous().Span)), nil
+}
+
+func (p *RDParser) arguments() ([]ast.Expr, error) {
+	var exprs []ast.Expr
+	var expr ast.Expr
+	var err error
+
+	for {
+		if expr, err = p.expression(); err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+		if !p.advanceIfMatch(token.Comma) {
+			break
+		}
+	}
+	return exprs, nil
+}
+
+func (p *RDParser) interpolation() (ast.Expr, error) {
+	var parts []ast.Expr
+
+	start := p.peek()
+	p.advance()
+	for {
+		if segment := p.previous(); segment.Literal != "" {
+			parts = append(parts, setSpan(&ast.LiteralExpr{Value: segment.Literal}, segment.Span))
+		}
+		// Nothing between "${" and "}"
+		if p.match(token.InterpolationMiddle) || p.match(token.InterpolationEnd) {
+			return nil, p.emitParsingError("empty interpolated expression")
+		}
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, expr)
+
+		if p.advanceIfMatch(token.InterpolationEnd) {
+			break
+		}
+		if !p.advanceIfMatch(token.InterpolationMiddle) {
+			return nil, p.emitParsingError("interpolated expression missing \"}\"")
+		}
+	}
+	if segment := p.previous(); segment.Literal != "" {
+		parts = append(parts, setSpan(&ast.LiteralExpr{Value: segment.Literal}, segment.Span))
+	}
+	return setSpan(&ast.InterpolationExpr{Parts: parts}, p.spanFrom(start)), nil
+}
+
+// mapLiteral parses the entries of a map, after its "{"
+func (p *RDParser) mapLiteral(start *token.Token) (ast.Expr, error) {
+	var keys []ast.Expr
+	var values []ast.Expr
+	var key ast.Expr
+	var value ast.Expr
+	var err error
+
+	for !p.match(token.RightBrace) {
+		if key, err = p.expression(); err != nil {
+			return nil, err
+		}
+		if !p.advanceIfMatch(token.Colon) {
+			return nil, p.emitParsingError("map entry missing \":\"")
+		}
+		if value, err = p.expression(); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+		if !p.advanceIfMatch(token.Comma) {
+			break
+		}
+	}
+	if !p.advanceIfMatch(token.RightBrace) {
+		return nil, p.emitParsingError("map missing \"}\"")
+	}
+	return setSpan(&ast.MapExpr{Keys: keys, Values: values}, p.spanFrom(start)), nil
+}
+
+func (p *RDParser) primary() (ast.Expr, error) {
+	var expr ast.Expr
+	var err error
+
+	start := p.peek()
+	if p.advanceIfMatch(token.False) {
+		return setSpan(&ast.LiteralExpr{Value: false}, p.previous().Span), nil
+	}
+	if p.advanceIfMatch(token.True) {
+		return setSpan(&ast.LiteralExpr{Value: true}, p.previous().Span), nil
+	}
+	if p.advanceIfMatch(token.Nil) {
+		return setSpan(&ast.LiteralExpr{Value: nil}, p.previous().Span), nil
+	}
+	if p.advanceIfMatch(token.String, token.Number) {
+		return setSpan(&ast.LiteralExpr{Value: p.previous().Literal}, p.previous().Span), nil
+	}
+	if p.match(token.Interpolation) {
+		return p.interpolation()
+	}
+	if p.advanceIfMatch(token.LeftBracket) {
+		var elements []ast.Expr
+		for !p.match(token.RightBracket) {
+			if expr, err = p.expression(); err != nil {
+				return nil, err
+			}
+			elements = append(elements, expr)
+			if !p.advanceIfMatch(token.Comma) {
+				break
+			}
+		}
+		if !p.advanceIfMatch(token.RightBracket) {
+			return nil, p.emitParsingError("list missing \"]\"")
+		}
+		return setSpan(&ast.ListExpr{Elements: elements}, p.spanFrom(start)), nil
+	}
+	if p.advanceIfMatch(token.LeftBrace) {
+		return p.mapLiteral(start)
+	}
+	if p.advanceIfMatch(token.LeftParen) {
+		if expr, err = p.expression(); err != nil {
+			return nil, err
+		}
+		if !p.match(token.RightParen) {
+			return nil, p.emitParsingError("grouping expr missing \")\"")
+		}
+		p.advance()
+		return setSpan(&ast.GroupingExpr{Child: expr}, p.spanFrom(start)), nil
+	}
+	if p.advanceIfMatch(token.Identifier) {
+		return setSpan(&ast.VariableExpr{Name: p.previous()}, p.previous().Span), nil
+	}
+	if p.advanceIfMatch(token.This) {
+		return setSpan(&ast.ThisExpr{}, p.previous().Span), nil
+	}
+	if p.advanceIfMatch(token.Super) {
+		if !p.advanceIfMatch(token.Dot) {
+			return nil, p.emitParsingError("super missing a \".\"")
+		}
+		if !p.advanceIfMatch(token.Identifier) {
+			return nil, p.emitParsingError("super missing an identifier")
+		}
+		return setSpan(&ast.SuperExpr{Property: p.previous()}, p.spanFrom(start)), nil
+	}
+	return nil, p.emitParsingError("expect a valid primary expr")
+}
@@ -0,0 +1,469 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/ast"
+	"github.com/StanleyGY/Lox/gLox/scanner"
+	"github.com/StanleyGY/Lox/gLox/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser(t *testing.T) {
+	t.Run("Test single stmt with binary expr", func(t *testing.T) {
+		parser := &RDParser{}
+		stmts, err := parser.Parse([]*token.Token{
+			{Type: token.Number, Literal: 1},
+			{Type: token.Plus, Lexeme: "+"},
+			{Type: token.Number, Literal: 1},
+			{Type: token.SemiColon},
+		})
+		assert.NoError(t, err)
+
+		printer := &ast.Printer{}
+		res := printer.PrettyPrintStmt(stmts[0])
+		assert.Equal(t, "(+ 1 1)", res)
+	})
+
+	t.Run("Test single stmt with unary expr", func(t *testing.T) {
+		parser := &RDParser{}
+		stmts, err := parser.Parse([]*token.Token{
+			{Type: token.Bang, Lexeme: "!"},
+			{Type: token.Bang, Lexeme: "!"},
+			{Type: token.Number, Literal: 1},
+			{Type: token.SemiColon},
+		})
+		assert.NoError(t, err)
+
+		printer := &ast.Printer{}
+		res := printer.PrettyPrintStmt(stmts[0])
+		assert.Equal(t, "(! (! 1))", res)
+	})
+
+	t.Run("Test single stmt with comparison expr", func(t *testing.T) {
+		parser := &RDParser{}
+		stmts, err := parser.Parse([]*token.Token{
+			{Type: token.String, Literal: "3"},
+			{Type: token.BangEqual, Lexeme: "!="},
+			{Type: token.Number, Literal: 1},
+			{Type: token.SemiColon},
+		})
+		assert.NoError(t, err)
+
+		printer := &ast.Printer{}
+		res := printer.PrettyPrintStmt(stmts[0])
+		assert.Equal(t, "(!= \"3\" 1)", res)
+	})
+
+	t.Run("Test single stmt with factor expr", func(t *testing.T) {
+		parser := &RDParser{}
+		stmts, err := parser.Parse([]*token.Token{
+			{Type: token.Number, Literal: 3},
+			{Type: token.Star, Lexeme: "*"},
+			{Type: token.Number, Literal: 1.6},
+			{Type: token.SemiColon},
+		})
+		assert.NoError(t, err)
+
+		printer := &ast.Printer{}
+		res := printer.PrettyPrintStmt(stmts[0])
+		assert.Equal(t, "(* 3 1.6)", res)
+	})
+
+	t.Run("Test single stmt with primary expr", func(t *testing.T) {
+		parser := &RDParser{}
+		printer := &ast.Printer{}
+
+		stmts, _ := parser.Parse([]*token.Token{
+			{Type: token.False, Lexeme: "false"},
+			{Type: token.SemiColon},
+		})
+		res := printer.PrettyPrintStmt(stmts[0])
+		assert.Equal(t, "false", res)
+
+		stmts, _ = parser.Parse([]*token.Token{
+			{Type: token.True, Lexeme: "true"},
+			{Type: token.SemiColon},
+		})
+		res = printer.PrettyPrintStmt(stmts[0])
+		assert.Equal(t, "true", res)
+
+		stmts, _ = parser.Parse([]*token.Token{
+			{Type: token.Nil, Lexeme: "nil"},
+			{Type: token.SemiColon},
+		})
+		res = printer.PrettyPrintStmt(stmts[0])
+		assert.Equal(t, "nil", res)
+	})
+
+	t.Run("Test multiple stmts", func(t *testing.T) {
+		parser := &RDParser{}
+		stmts, _ := parser.Parse([]*token.Token{
+			{Type: token.Number, Literal: 3},
+			{Type: token.SemiColon},
+			{Type: token.Number, Literal: 4},
+			{Type: token.SemiColon},
+		})
+		assert.Equal(t, len(stmts), 2)
+	})
+
+	t.Run("Test single stmt with error expr not having matched rule", func(t *testing.T) {
+		parser := &RDParser{}
+		_, err := parser.Parse([]*token.Token{
+			{Type: token.Number, Literal: 3},
+			{Type: token.Number, Literal: 1.6},
+			{Type: token.SemiColon},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Test single stmt with missing semicolon", func(t *testing.T) {
+		parser := &RDParser{}
+		_, err := parser.Parse([]*token.Token{
+			{Type: token.Number, Literal: 3},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Test source spans", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		tokens, _ := s.Scan("if (a)\n  print this.b + 1;")
+
+		parser := &RDParser{}
+		stmts, err := parser.Parse(tokens)
+		assert.NoError(t, err)
+
+		ifStmt := stmts[0].(*ast.IfStmt)
+		assert.Equal(t, token.Position{Offset: 0, Line: 1, Column: 1}, ifStmt.Span().Start)
+		assert.Equal(t, token.Position{Offset: 26, Line: 2, Column: 20}, ifStmt.Span().End)
+
+		printStmt := ifStmt.ThenBranch.(*ast.PrintStmt)
+		assert.Equal(t, token.Position{Offset: 9, Line: 2, Column: 3}, printStmt.Span().Start)
+
+		binary := printStmt.Child.(*ast.BinaryExpr)
+		assert.Equal(t, token.Position{Offset: 15, Line: 2, Column: 9}, binary.Span().Start)
+		assert.Equal(t, token.Position{Offset: 25, Line: 2, Column: 19}, binary.Span().End)
+
+		this := binary.Left.(*ast.GetPropertyExpr).Object
+		assert.Equal(t, token.Position{Offset: 19, Line: 2, Column: 13}, this.Span().End)
+	})
+
+	t.Run("Test parsing error position", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		tokens, _ := s.Scan("var a = 1;\nvar = 2;")
+
+		parser := &RDParser{}
+		_, err := parser.Parse(tokens)
+		errs := err.(ParsingErrors)
+		assert.Equal(t, 1, len(errs))
+		assert.Equal(t, 2, errs[0].Span.Start.Line)
+		assert.Equal(t, 5, errs[0].Span.Start.Column)
+	})
+
+	t.Run("Test error recovery", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		tokens, _ := s.Scan("var = 1;\nprint 2;\nprint (1;\nprint 4 print 5;\nprint 6;")
+
+		parser := &RDParser{}
+		stmts, err := parser.Parse(tokens)
+		errs := err.(ParsingErrors)
+		assert.Equal(t, 3, len(errs))
+		assert.Equal(t, 1, errs[0].Span.Start.Line)
+		assert.Equal(t, 3, errs[1].Span.Start.Line)
+		assert.Equal(t, 4, errs[2].Span.Start.Line)
+
+		printer := &ast.Printer{}
+		var outputs []string
+		for _, stmt := range stmts {
+			outputs = append(outputs, printer.PrettyPrintStmt(stmt))
+		}
+		// The statement after a missing ";" is kept
+		assert.Equal(t, []string{"(print 2)", "(print 5)", "(print 6)"}, outputs)
+	})
+
+	t.Run("Test error recovery in block", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		tokens, _ := s.Scan("{ var = 1; print 2; } print 3; {")
+
+		parser := &RDParser{}
+		stmts, err := parser.Parse(tokens)
+		assert.Equal(t, 2, len(err.(ParsingErrors)))
+		assert.Equal(t, 2, len(stmts))
+	})
+
+	t.Run("Test string interpolation", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		tokens, _ := s.Scan(`print "a ${b} c ${1 + 2}";`)
+
+		parser := &RDParser{}
+		stmts, err := parser.Parse(tokens)
+		assert.NoError(t, err)
+
+		printer := &ast.Printer{}
+		assert.Equal(t, `(print (interpolate "a " b " c " (+ 1 2)))`, printer.PrettyPrintStmt(stmts[0]))
+		assert.Equal(t, token.Position{Offset: 6, Line: 1, Column: 7}, stmts[0].(*ast.PrintStmt).Child.Span().Start)
+		assert.Equal(t, token.Position{Offset: 25, Line: 1, Column: 26}, stmts[0].(*ast.PrintStmt).Child.Span().End)
+
+		tokens, _ = s.Scan(`print "a ${b c}";`)
+		_, err = parser.Parse(tokens)
+		assert.Error(t, err)
+
+		for _, src := range []string{`print "a ${}";`, `print "${} ${b}";`, `print "${ "${}" }";`} {
+			tokens, _ = s.Scan(src)
+			_, err = parser.Parse(tokens)
+			errs := err.(ParsingErrors)
+			assert.Equal(t, 1, len(errs))
+			assert.Equal(t, "empty interpolated expression", errs[0].Reason)
+		}
+
+		// The rest of the string is not an operand
+		tokens, _ = s.Scan(`print "a ${1 + } b";`)
+		_, err = parser.Parse(tokens)
+		errs := err.(ParsingErrors)
+		assert.Equal(t, 1, len(errs))
+		assert.Equal(t, "expect a valid primary expr", errs[0].Reason)
+		assert.Equal(t, 16, errs[0].Span.Start.Column)
+	})
+
+	t.Run("Test doc comments", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		tokens, _ := s.Scan(`
+			/// A point.
+			class Point {
+				/// Builds a point.
+				init(x) { this.x = x; }
+				norm() { return this.x; }
+			}
+			/// The origin.
+			var origin = Point(0);
+			/// Prints a point.
+			fun show(p) { print p.x; }
+			print 1;
+		`)
+
+		parser := &RDParser{}
+		stmts, err := parser.Parse(tokens)
+		assert.NoError(t, err)
+
+		class := stmts[0].(*ast.ClassDeclStmt)
+		assert.Equal(t, "A point.", class.Doc)
+		assert.Equal(t, "Builds a point.", class.Methods[0].Doc)
+		assert.Equal(t, "", class.Methods[1].Doc)
+		assert.Equal(t, "The origin.", stmts[1].(*ast.VarDeclStmt).Doc)
+		assert.Equal(t, "Prints a point.", stmts[2].(*ast.FuncDeclStmt).Doc)
+	})
+
+	t.Run("Test operator precedence", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		printer := &ast.Printer{}
+		for src, expected := range map[string]string{
+			"2 ** 3 ** 2;":        "(** 2 (** 3 2))",
+			"-2 ** 2;":            "(- (** 2 2))",
+			"2 ** -1;":            "(** 2 (- 1))",
+			"a * b ** c % d;":     "(% (* a (** b c)) d)",
+			"1 | 2 ^ 3 & 4 << 5;": "(| 1 (^ 2 (& 3 (<< 4 5))))",
+			"1 << 2 + 3 >> 4;":    "(>> (<< 1 (+ 2 3)) 4)",
+			"a & b == c | d;":     "(== (& a b) (| c d))",
+			"~a & ~b < c;":        "(< (& (~ a) (~ b)) c)",
+			"f(x) ** 2 ** g.y;":   "(** (call f x) (** 2 (get-prop g \"y\")))",
+		} {
+			tokens, _ := s.Scan(src)
+			stmts, err := (&RDParser{}).Parse(tokens)
+			assert.NoError(t, err, src)
+			assert.Equal(t, expected, printer.PrettyPrintStmt(stmts[0]), src)
+		}
+	})
+
+	t.Run("Test update expressions", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		printer := &ast.Printer{}
+		for src, expected := range map[string]string{
+			"x += 1;":          "(+= x 1)",
+			"a.b.c *= y -= 2;": "(*= (get-prop (get-prop a \"b\") \"c\") (-= y 2))",
+			"++x;":             "(pre++ x)",
+			"x--;":             "(post-- x)",
+			"-x++;":            "(- (post++ x))",
+			"--a.b;":           "(pre-- (get-prop a \"b\"))",
+			"x++ ** 2;":        "(** (post++ x) 2)",
+		} {
+			tokens, _ := s.Scan(src)
+			stmts, err := (&RDParser{}).Parse(tokens)
+			assert.NoError(t, err, src)
+			assert.Equal(t, expected, printer.PrettyPrintStmt(stmts[0]), src)
+		}
+
+		for _, src := range []string{"1 += 2;", "f() -= 1;", "++1;", "(x)++;", "x++ ++;"} {
+			tokens, _ := s.Scan(src)
+			_, err := (&RDParser{}).Parse(tokens)
+			assert.Error(t, err, src)
+		}
+	})
+
+	t.Run("Test lists", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		printer := &ast.Printer{}
+		for src, expected := range map[string]string{
+			"[];":               "(list)",
+			"[1, \"a\", [x],];": "(list 1 \"a\" (list x))",
+			"xs[0];":            "(index xs 0)",
+			"xs[-1][i + 1];":    "(index (index xs (- 1)) (+ i 1))",
+			"f()[0].y;":         "(get-prop (index (call f) 0) \"y\")",
+			"xs[0] = ys[1];":    "(set-index xs 0 (index ys 1))",
+			"xs[i] += 1;":       "(+= (index xs i) 1)",
+			"xs[i]++;":          "(post++ (index xs i))",
+			"xs[1:3];":          "(slice xs 1 3)",
+			"xs[:-1];":          "(slice xs nil (- 1))",
+			"xs[1:];":           "(slice xs 1 nil)",
+			"xs[:];":            "(slice xs nil nil)",
+		} {
+			tokens, _ := s.Scan(src)
+			stmts, err := (&RDParser{}).Parse(tokens)
+			assert.NoError(t, err, src)
+			assert.Equal(t, expected, printer.PrettyPrintStmt(stmts[0]), src)
+		}
+
+		for _, src := range []string{"[1, 2;", "[,];", "xs[];", "xs[1;", "xs[1:2:3];", "xs[1:3] = 2;"} {
+			tokens, _ := s.Scan(src)
+			_, err := (&RDParser{}).Parse(tokens)
+			assert.Error(t, err, src)
+		}
+	})
+
+	t.Run("Test maps", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		printer := &ast.Printer{}
+		for src, expected := range map[string]string{
+			"var m = {};":                "(assign \"m\" (map))",
+			"print {\"a\": 1, b: [2],};": "(print (map \"a\" 1 b (list 2)))",
+			"m[\"a\"] = {1: {}};":        "(set-index m \"a\" (map 1 (map)))",
+		} {
+			tokens, _ := s.Scan(src)
+			stmts, err := (&RDParser{}).Parse(tokens)
+			assert.NoError(t, err, src)
+			assert.Equal(t, expected, printer.PrettyPrintStmt(stmts[0]), src)
+		}
+
+		// A "{" in statement position is still a block
+		tokens, _ := s.Scan("{ 1; }")
+		stmts, err := (&RDParser{}).Parse(tokens)
+		assert.NoError(t, err)
+		assert.IsType(t, &ast.BlockStmt{}, stmts[0])
+
+		for _, src := range []string{"print {1};", "print {1: 2;", "print {1: 2 3: 4};", "print {,};"} {
+			tokens, _ := s.Scan(src)
+			_, err := (&RDParser{}).Parse(tokens)
+			assert.Error(t, err, src)
+		}
+	})
+
+	t.Run("Test for-in loops", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		printer := &ast.Printer{}
+		for src, expected := range map[string]string{
+			"for (var x in xs) print x;":           "((for-in \"x\" xs)(print x))",
+			"for (var c in \"ab\" + s) { break; }": "((for-in \"c\" (+ \"ab\" s))(break))",
+		} {
+			tokens, _ := s.Scan(src)
+			stmts, err := (&RDParser{}).Parse(tokens)
+			assert.NoError(t, err, src)
+			assert.Equal(t, expected, printer.PrettyPrintStmt(stmts[0]), src)
+		}
+
+		for _, src := range []string{"for (x in xs) print x;", "for (var x in xs print x;", "for (var x in) print x;"} {
+			tokens, _ := s.Scan(src)
+			_, err := (&RDParser{}).Parse(tokens)
+			assert.Error(t, err, src)
+		}
+	})
+
+	t.Run("Test continue statements", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		printer := &ast.Printer{}
+		for src, expected := range map[string]string{
+			"while (a) continue;":                      "((while a)(continue))",
+			"for (;;) continue;":                       "((while true)(continue))",
+			"for (; i < 3; i++) { continue; }":         "((while (< i 3))(continue)(increment (post++ i)))",
+			"for (var x in xs) { print x; continue; }": "((for-in \"x\" xs)(print x)(continue))",
+		} {
+			tokens, _ := s.Scan(src)
+			stmts, err := (&RDParser{}).Parse(tokens)
+			assert.NoError(t, err, src)
+			assert.Equal(t, expected, printer.PrettyPrintStmt(stmts[0]), src)
+		}
+
+		tokens, _ := s.Scan("while (a) continue")
+		_, err := (&RDParser{}).Parse(tokens)
+		assert.Error(t, err)
+	})
+
+	t.Run("Test labeled loops", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		printer := &ast.Printer{}
+		for src, expected := range map[string]string{
+			"outer: while (a) break outer;":             "(label \"outer\" ((while a)(break \"outer\")))",
+			"rows: for (; i < 3; i++) continue rows;":   "(label \"rows\" ((while (< i 3))(continue \"rows\")(increment (post++ i))))",
+			"each: for (var x in xs) { continue; }":     "(label \"each\" ((for-in \"x\" xs)(continue)))",
+			"a: for (var i = 0; ; ) break;":             "(assign \"i\" 0)(label \"a\" ((while true)(break)))",
+			"x: while (a) y: while (b) { continue x; }": "(label \"x\" ((while a)(label \"y\" ((while b)(continue \"x\")))))",
+		} {
+			tokens, _ := s.Scan(src)
+			stmts, err := (&RDParser{}).Parse(tokens)
+			assert.NoError(t, err, src)
+			assert.Equal(t, expected, printer.PrettyPrintStmt(stmts[0]), src)
+		}
+
+		for _, src := range []string{"a: print 1;", "a: { while (b) {} }", "while (a) break 1;", "while (a) continue b c;"} {
+			tokens, _ := s.Scan(src)
+			_, err := (&RDParser{}).Parse(tokens)
+			assert.Error(t, err, src)
+		}
+	})
+
+	t.Run("Test error recovery before a closing brace", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		tokens, _ := s.Scan("{ print 1 } print 2;")
+
+		parser := &RDParser{}
+		stmts, err := parser.Parse(tokens)
+		errs := err.(ParsingErrors)
+		assert.Equal(t, 1, len(errs))
+		assert.Equal(t, 11, errs[0].Span.Start.Column)
+
+		assert.Equal(t, 2, len(stmts))
+		assert.Empty(t, stmts[0].(*ast.BlockStmt).Stmts)
+		printer := &ast.Printer{}
+		assert.Equal(t, "(print 2)", printer.PrettyPrintStmt(stmts[1]))
+
+		// A stray "}" is still skipped
+		tokens, _ = s.Scan("} print 3;")
+		stmts, err = parser.Parse(tokens)
+		assert.Equal(t, 1, len(err.(ParsingErrors)))
+		assert.Equal(t, 1, len(stmts))
+	})
+
+	t.Run("Test error recovery reports each mistake once", func(t *testing.T) {
+		s := &scanner.ScannerImpl{}
+		tokens, _ := s.Scan("var a = ;\nprint 1\nfun f( { }\nvar b = 2;\nclass { }\nprint b;")
+
+		parser := &RDParser{}
+		stmts, err := parser.Parse(tokens)
+		var errs []string
+		for _, e := range err.(ParsingErrors) {
+			errs = append(errs, fmt.Sprintf("%d:%d %s", e.Span.Start.Line, e.Span.Start.Column, e.Reason))
+		}
+		assert.Equal(t, []string{
+			"1:9 expect a valid primary expr",
+			"3:1 missing \";\"",
+			"3:8 func declaration missing \")\"",
+			"5:7 class declaration missing name",
+		}, errs)
+
+		printer := &ast.Printer{}
+		var outputs []string
+		for _, stmt := range stmts {
+			outputs = append(outputs, printer.PrettyPrintStmt(stmt))
+		}
+		assert.Equal(t, []string{`(assign "b" 2)`, "(print b)"}, outputs)
+	})
+}
@@ -0,0 +1,514 @@
+package resolver
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/ast"
+	"github.com/StanleyGY/Lox/gLox/token"
+	"maps"
+)
+
+// Binder records the resolved scope distance of a variable reference.
+// It is implemented by the interpreter that later evaluates the same AST.
+type Binder interface {
+	Resolve(expr ast.Expr, dist int)
+	// GlobalNames lists the globals that are defined before resolution starts
+	GlobalNames() []string
+}
+
+// Resolver performs the semantic analysis that resolves a variable always to the same declaration,
+// by calculating number of "hops" away the declared variable will be in the environment chain.
+//
+// An example chain of scopes: Global -> Block -> Class Decl -> Class Method
+type Resolver struct {
+	scopes         []map[string]bool
+	predefined     map[string]bool
+	intepreter     Binder
+	enclosingFunc  *ast.FuncDeclStmt
+	enclosingClass *ast.ClassDeclStmt
+	// Loops around the current statement in the current function, from the outermost.
+	// Each one is either a *ast.WhileStmt or a *ast.ForInStmt.
+	enclosingLoops []ast.Stmt
+}
+
+type SemanticsError struct {
+	Reason string
+	Span   token.Span
+}
+
+func (e SemanticsError) Error() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("[line %d] Semantics error: ", e.Span.Start.Line))
+	buf.WriteString(e.Reason)
+	return buf.String()
+}
+
+func MakeResolver(interpreter Binder) *Resolver {
+	globals := make(map[string]bool)
+	predefined := make(map[string]bool)
+	for _, name := range interpreter.GlobalNames() {
+		globals[name] = true
+		predefined[name] = true
+	}
+	scopes := []map[string]bool{globals}
+	return &Resolver{scopes: scopes, predefined: predefined, intepreter: interpreter, enclosingFunc: nil}
+}
+
+func (r *Resolver) Resolve(stmts []ast.Stmt) error {
+	globals := maps.Clone(r.scopes[0])
+	predefined := maps.Clone(r.predefined)
+
+	for _, s := range stmts {
+		if err := s.Accept(r); err != nil {
+			// Drop whatever scopes and globals were declared when the error happened,
+			// so the resolver can be reused for the next input (e.g. in a REPL)
+			r.reset(globals, predefined)
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) reset(globals map[string]bool, predefined map[string]bool) {
+	r.scopes = []map[string]bool{globals}
+	r.predefined = predefined
+	r.enclosingFunc = nil
+	r.enclosingClass = nil
+	r.enclosingLoops = nil
+}
+
+// SyncGlobals drops the global declarations that the interpreter doesn't hold,
+// because a runtime error stopped the evaluation before reaching them
+func (r *Resolver) SyncGlobals() {
+	bound := make(map[string]bool)
+	for _, name := range r.intepreter.GlobalNames() {
+		bound[name] = true
+	}
+	for name := range r.scopes[0] {
+		if !bound[name] {
+			delete(r.scopes[0], name)
+		}
+	}
+}
+
+func (r *Resolver) beginScope() {
+	r.scopes = append(r.scopes, make(map[string]bool))
+}
+
+func (r *Resolver) endScope() {
+	r.scopes = r.scopes[0 : len(r.scopes)-1]
+}
+
+func (r *Resolver) declare(name string) bool {
+	if _, declared := r.scopes[len(r.scopes)-1][name]; declared {
+		// A predefined global (e.g. a native function) can be redeclared once by the program
+		if len(r.scopes) > 1 || !r.predefined[name] {
+			return false
+		}
+		delete(r.predefined, name)
+	}
+	r.scopes[len(r.scopes)-1][name] = false
+	return true
+}
+
+func (r *Resolver) define(name string) {
+	r.scopes[len(r.scopes)-1][name] = true
+}
+
+func (r *Resolver) searchScopes(name string) (int, bool) {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		defined, ok := r.scopes[i][name]
+		if ok && defined {
+			return len(r.scopes) - 1 - i, true
+		}
+	}
+	return 0, false
+}
+
+func (r *Resolver) VisitVarDeclStmt(stmt *ast.VarDeclStmt) error {
+	// A variable declaration introduces a new binding in current scope
+	if !r.declare(stmt.Name.Lexeme) {
+		return &SemanticsError{Reason: fmt.Sprintf("redefining variable: %s", stmt.Name.Lexeme), Span: stmt.Name.Span}
+	}
+	if stmt.Initializer != nil {
+		if _, err := stmt.Initializer.Accept(r); err != nil {
+			return err
+		}
+	}
+	r.define(stmt.Name.Lexeme)
+	return nil
+}
+
+func (r *Resolver) VisitFunDeclStmt(stmt *ast.FuncDeclStmt) error {
+	// A function declaration introduces a new binding in the block/global level,
+	// and creates a new scope for function body
+	if !r.declare(stmt.Name.Lexeme) {
+		return &SemanticsError{Reason: fmt.Sprintf("redefining function: %s", stmt.Name.Lexeme), Span: stmt.Name.Span}
+	}
+	r.define(stmt.Name.Lexeme)
+
+	r.beginScope()
+	lastEnclosingFunc := r.enclosingFunc
+	r.enclosingFunc = stmt
+	// A loop around the declaration cannot be exited from the function body
+	lastEnclosingLoops := r.enclosingLoops
+	r.enclosingLoops = nil
+	for _, param := range stmt.Params {
+		r.declare(param.Lexeme)
+		r.define(param.Lexeme)
+	}
+	if err := stmt.Body.Accept(r); err != nil {
+		return err
+	}
+	r.enclosingFunc = lastEnclosingFunc
+	r.enclosingLoops = lastEnclosingLoops
+	r.endScope()
+	return nil
+}
+
+func (r *Resolver) VisitClassDeclStmt(stmt *ast.ClassDeclStmt) error {
+	if !r.declare(stmt.Name.Lexeme) {
+		return &SemanticsError{Reason: fmt.Sprintf("redefining class: %s", stmt.Name.Lexeme), Span: stmt.Name.Span}
+	}
+	r.define(stmt.Name.Lexeme)
+
+	lastEnclosingClass := r.enclosingClass
+	r.enclosingClass = stmt
+
+	if stmt.SuperClass != nil {
+		if _, err := stmt.SuperClass.Accept(r); err != nil {
+			return err
+		}
+
+	}
+	r.beginScope()
+	r.define("super")
+	r.define("this")
+	for _, method := range stmt.Methods {
+		if err := method.Accept(r); err != nil {
+			return err
+		}
+	}
+	r.endScope()
+
+	r.enclosingClass = lastEnclosingClass
+	return nil
+}
+
+func (r *Resolver) VisitInlineExprStmt(stmt *ast.InlineExprStmt) error {
+	if _, err := stmt.Child.Accept(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *Resolver) VisitPrintStmt(stmt *ast.PrintStmt) error {
+	if _, err := stmt.Child.Accept(r); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *Resolver) VisitBlockStmt(stmt *ast.BlockStmt) error {
+	// A block introduces a new scope
+	r.beginScope()
+	for _, s := range stmt.Stmts {
+		if err := s.Accept(r); err != nil {
+			return err
+		}
+	}
+	r.endScope()
+	return nil
+}
+
+func (r *Resolver) VisitIfStmt(stmt *ast.IfStmt) error {
+	if _, err := stmt.Condition.Accept(r); err != nil {
+		return err
+	}
+	if err := stmt.ThenBranch.Accept(r); err != nil {
+		return err
+	}
+	if stmt.ElseBranch != nil {
+		if err := stmt.ElseBranch.Accept(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) VisitWhileStmt(stmt *ast.WhileStmt) error {
+	if _, err := stmt.Condition.Accept(r); err != nil {
+		return err
+	}
+	if err := r.loopBody(stmt, stmt.Label, stmt.Body); err != nil {
+		return err
+	}
+	if stmt.Increment != nil {
+		if _, err := stmt.Increment.Accept(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) VisitForInStmt(stmt *ast.ForInStmt) error {
+	if _, err := stmt.Iterable.Accept(r); err != nil {
+		return err
+	}
+
+	// The loop variable lives in its own scope, around the body
+	r.beginScope()
+	defer r.endScope()
+	r.declare(stmt.Name.Lexeme)
+	r.define(stmt.Name.Lexeme)
+
+	return r.loopBody(stmt, stmt.Label, stmt.Body)
+}
+
+// loopBody resolves the body of `loop`, which "break" and "continue" can target
+func (r *Resolver) loopBody(loop ast.Stmt, label *token.Token, body ast.Stmt) error {
+	if label != nil && r.findLoop(label) != nil {
+		return &SemanticsError{Reason: fmt.Sprintf("label %s is already used by an enclosing loop", label.Lexeme), Span: label.Span}
+	}
+	lastEnclosingLoops := r.enclosingLoops
+	r.enclosingLoops = append(r.enclosingLoops, loop)
+	defer func() { r.enclosingLoops = lastEnclosingLoops }()
+	return body.Accept(r)
+}
+
+// findLoop returns the enclosing loop named `label`, or nil
+func (r *Resolver) findLoop(label *token.Token) ast.Stmt {
+	for _, loop := range r.enclosingLoops {
+		var loopLabel *token.Token
+		switch loop := loop.(type) {
+		case *ast.WhileStmt:
+			loopLabel = loop.Label
+		case *ast.ForInStmt:
+			loopLabel = loop.Label
+		}
+		if loopLabel != nil && loopLabel.Lexeme == label.Lexeme {
+			return loop
+		}
+	}
+	return nil
+}
+
+// checkJump validates a "break" or a "continue" with an optional label
+func (r *Resolver) checkJump(keyword string, label *token.Token, span token.Span) error {
+	if len(r.enclosingLoops) == 0 {
+		return &SemanticsError{Reason: fmt.Sprintf("%s must be in a loop", keyword), Span: span}
+	}
+	if label != nil && r.findLoop(label) == nil {
+		return &SemanticsError{Reason: fmt.Sprintf("label %s does not name an enclosing loop", label.Lexeme), Span: label.Span}
+	}
+	return nil
+}
+
+func (r *Resolver) VisitReturnStmt(stmt *ast.ReturnStmt) error {
+	if r.enclosingFunc == nil {
+		return &SemanticsError{Reason: "return must be inside of a function", Span: stmt.Span()}
+	}
+
+	if stmt.Value != nil {
+		if r.enclosingFunc.Name.Lexeme == "init" {
+			// A init function declared in class should just be a return without value in code
+			return &SemanticsError{Reason: "class initializer should return nothing", Span: stmt.Span()}
+		}
+		if _, err := stmt.Value.Accept(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) VisitBreakStmt(stmt *ast.BreakStmt) error {
+	return r.checkJump("break", stmt.Label, stmt.Span())
+}
+
+func (r *Resolver) VisitContinueStmt(stmt *ast.ContinueStmt) error {
+	return r.checkJump("continue", stmt.Label, stmt.Span())
+}
+
+func (r *Resolver) VisitBinaryExpr(expr *ast.BinaryExpr) (interface{}, error) {
+	if _, err := expr.Left.Accept(r); err != nil {
+		return nil, err
+	}
+	if _, err := expr.Right.Accept(r); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitUnaryExpr(expr *ast.UnaryExpr) (interface{}, error) {
+	if _, err := expr.Right.Accept(r); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitLogicalExpr(expr *ast.LogicExpr) (interface{}, error) {
+	if _, err := expr.Left.Accept(r); err != nil {
+		return nil, err
+	}
+	if _, err := expr.Right.Accept(r); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitGroupingExpr(expr *ast.GroupingExpr) (interface{}, error) {
+	if _, err := expr.Child.Accept(r); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitLiteralExpr(expr *ast.LiteralExpr) (interface{}, error) {
+	return nil, nil
+}
+
+func (r *Resolver) VisitInterpolationExpr(expr *ast.InterpolationExpr) (interface{}, error) {
+	for _, part := range expr.Parts {
+		if _, err := part.Accept(r); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitAssignExpr(expr *ast.AssignExpr) (interface{}, error) {
+	if _, err := expr.Value.Accept(r); err != nil {
+		return nil, err
+	}
+
+	dist, defined := r.searchScopes(expr.Name.Lexeme)
+	if !defined {
+		return nil, &SemanticsError{Reason: fmt.Sprintf("undefined variable: %s", expr.Name.Lexeme), Span: expr.Span()}
+	}
+	r.intepreter.Resolve(expr, dist)
+	return nil, nil
+}
+
+func (r *Resolver) VisitListExpr(expr *ast.ListExpr) (interface{}, error) {
+	for _, element := range expr.Elements {
+		if _, err := element.Accept(r); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitMapExpr(expr *ast.MapExpr) (interface{}, error) {
+	for idx := range expr.Keys {
+		if _, err := expr.Keys[idx].Accept(r); err != nil {
+			return nil, err
+		}
+		if _, err := expr.Values[idx].Accept(r); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitIndexGetExpr(expr *ast.IndexGetExpr) (interface{}, error) {
+	if _, err := expr.Object.Accept(r); err != nil {
+		return nil, err
+	}
+	return expr.Index.Accept(r)
+}
+
+func (r *Resolver) VisitIndexSetExpr(expr *ast.IndexSetExpr) (interface{}, error) {
+	if _, err := expr.Object.Accept(r); err != nil {
+		return nil, err
+	}
+	if _, err := expr.Index.Accept(r); err != nil {
+		return nil, err
+	}
+	return expr.Value.Accept(r)
+}
+
+func (r *Resolver) VisitSliceExpr(expr *ast.SliceExpr) (interface{}, error) {
+	for _, e := range []ast.Expr{expr.Object, expr.Start, expr.End} {
+		if e == nil {
+			continue
+		}
+		if _, err := e.Accept(r); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitUpdateExpr(expr *ast.UpdateExpr) (interface{}, error) {
+	if _, err := expr.Value.Accept(r); err != nil {
+		return nil, err
+	}
+	// Resolving the target also resolves the variable it updates
+	if _, err := expr.Target.Accept(r); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitCallExpr(expr *ast.CallExpr) (interface{}, error) {
+	if _, err := expr.Callee.Accept(r); err != nil {
+		return nil, err
+	}
+	for _, argv := range expr.Arguments {
+		if _, err := argv.Accept(r); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitGetPropertyExpr(expr *ast.GetPropertyExpr) (interface{}, error) {
+	return expr.Object.Accept(r)
+}
+
+func (r *Resolver) VisitSetPropertyExpr(expr *ast.SetPropertyExpr) (interface{}, error) {
+	if _, err := expr.Object.Accept(r); err != nil {
+		return nil, err
+	}
+	if _, err := expr.Value.Accept(r); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *Resolver) VisitVariableExpr(expr *ast.VariableExpr) (interface{}, error) {
+	if defined, declared := r.scopes[len(r.scopes)-1][expr.Name.Lexeme]; declared && !defined {
+		return nil, &SemanticsError{Reason: fmt.Sprintf("variable referencing itself in its own initializer: %s", expr.Name.Lexeme), Span: expr.Span()}
+	}
+
+	// Start from the innermost till the global scope, look for a matching name
+	dist, defined := r.searchScopes(expr.Name.Lexeme)
+	if !defined {
+		return nil, &SemanticsError{Reason: fmt.Sprintf("undefined variable: %s", expr.Name.Lexeme), Span: expr.Span()}
+	}
+	r.intepreter.Resolve(expr, dist)
+	return nil, nil
+}
+
+func (r *Resolver) VisitThisExpr(expr *ast.ThisExpr) (interface{}, error) {
+	dist, defined := r.searchScopes("this")
+	if !defined {
+		return nil, &SemanticsError{Reason: "unbounded \"this\"", Span: expr.Span()}
+	}
+	r.intepreter.Resolve(expr, dist)
+	return nil, nil
+}
+
+func (r *Resolver) VisitSuperExpr(expr *ast.SuperExpr) (interface{}, error) {
+	// Resolve `super` as if it were a variable
+	dist, defined := r.searchScopes("super")
+	if !defined {
+		return nil, &SemanticsError{Reason: "unbounded \"super\"", Span: expr.Span()}
+	}
+	// Check if this class has a super class
+	if r.enclosingClass.SuperClass == nil {
+		return nil, &SemanticsError{Reason: "calling super on a class that doesn't have a super class", Span: expr.Span()}
+	}
+	r.intepreter.Resolve(expr, dist)
+	return nil, nil
+}
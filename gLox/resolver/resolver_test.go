@@ -0,0 +1,84 @@
+package resolver
+
+import (
+	"github.com/StanleyGY/Lox/gLox/ast"
+	"github.com/StanleyGY/Lox/gLox/parser"
+	"github.com/StanleyGY/Lox/gLox/scanner"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// binder records the scope hops like the interpreter does
+type binder struct {
+	hops    map[ast.Expr]int
+	globals []string
+}
+
+func (b *binder) Resolve(expr ast.Expr, dist int) {
+	b.hops[expr] = dist
+}
+
+func (b *binder) GlobalNames() []string {
+	return b.globals
+}
+
+func parse(t *testing.T, src string) []ast.Stmt {
+	s := &scanner.ScannerImpl{}
+	tokens, err := s.Scan(src)
+	assert.NoError(t, err)
+	p := &parser.RDParser{}
+	stmts, err := p.Parse(tokens)
+	assert.NoError(t, err)
+	return stmts
+}
+
+func TestResolver(t *testing.T) {
+	t.Run("Test scope hops", func(t *testing.T) {
+		b := &binder{hops: make(map[ast.Expr]int), globals: []string{"clock"}}
+		stmts := parse(t, "var a = clock;\nfun f(x) { { return x + a; } }")
+		assert.NoError(t, MakeResolver(b).Resolve(stmts))
+
+		body := stmts[1].(*ast.FuncDeclStmt).Body.(*ast.BlockStmt)
+		ret := body.Stmts[0].(*ast.BlockStmt).Stmts[0].(*ast.ReturnStmt)
+		sum := ret.Value.(*ast.BinaryExpr)
+		// The parameters and the body of a function have separate scopes
+		assert.Equal(t, 2, b.hops[sum.Left])
+		assert.Equal(t, 3, b.hops[sum.Right])
+		assert.Equal(t, 0, b.hops[stmts[0].(*ast.VarDeclStmt).Initializer])
+	})
+
+	t.Run("Test semantic errors", func(t *testing.T) {
+		for src, reason := range map[string]string{
+			"print a;":                                       "undefined variable: a",
+			"a = 1;":                                         "undefined variable: a",
+			"{ var a = 1; var a = 2; }":                      "redefining variable: a",
+			"{ fun f() {} fun f() {} }":                      "redefining function: f",
+			"{ class A {} class A {} }":                      "redefining class: A",
+			"{ var a = a; }":                                 "variable referencing itself in its own initializer: a",
+			"return 1;":                                      "return must be inside of a function",
+			"class A { init() { return 1; } }":               "class initializer should return nothing",
+			"print this;":                                    "unbounded \"this\"",
+			"fun f() { super.g(); }":                         "unbounded \"super\"",
+			"class A { f() { super.f(); } }":                 "calling super on a class that doesn't have a super class",
+			"break;":                                         "break must be in a loop",
+			"while (true) { fun f() { continue; } }":         "continue must be in a loop",
+			"outer: while (true) break inner;":               "label inner does not name an enclosing loop",
+			"outer: while (true) outer: while (true) break;": "label outer is already used by an enclosing loop",
+		} {
+			b := &binder{hops: make(map[ast.Expr]int)}
+			err := MakeResolver(b).Resolve(parse(t, src))
+			semErr, ok := err.(*SemanticsError)
+			if assert.True(t, ok, src) {
+				assert.Equal(t, reason, semErr.Reason, src)
+			}
+		}
+	})
+
+	t.Run("Test failed resolution leaves globals unchanged", func(t *testing.T) {
+		b := &binder{hops: make(map[ast.Expr]int)}
+		r := MakeResolver(b)
+		assert.Error(t, r.Resolve(parse(t, "var a = 1;\nprint zz;")))
+		assert.Error(t, r.Resolve(parse(t, "print a;")))
+	})
+}
@@ -0,0 +1,679 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/token"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Scanner converts source code to tokens. Lexical errors are returned together as a ScanningErrors.
+type Scanner interface {
+	Scan(source string) ([]*token.Token, error)
+}
+
+var _ Scanner = &ScannerImpl{}
+
+// ScanningError is a lexical error. `Line` is the source line where it starts.
+type ScanningError struct {
+	Reason string
+	Span   token.Span
+	Line   string
+	// The source ends inside a string, an interpolation or a block comment,
+	// so more source could complete it
+	Unterminated bool
+}
+
+func (e ScanningError) Error() string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("Scanning failed at line %d, column %d: \n", e.Span.Start.Line, e.Span.Start.Column))
+	buf.WriteString(e.Line)
+	buf.WriteString("\n")
+	// Underline the lexeme, up to the end of the line
+	width := utf8.RuneCountInString(e.Line) - e.Span.Start.Column + 1
+	if e.Span.End.Line == e.Span.Start.Line {
+		width = min(width, e.Span.End.Column-e.Span.Start.Column)
+	}
+	width = max(1, width)
+	buf.WriteString(strings.Repeat(" ", e.Span.Start.Column-1))
+	buf.WriteString(strings.Repeat("^", width))
+	buf.WriteString("\nFailed reason: ")
+	buf.WriteString(e.Reason)
+	return buf.String()
+}
+
+// ScanningErrors lists all lexical errors found in a source
+type ScanningErrors []*ScanningError
+
+func (e ScanningErrors) Error() string {
+	var buf bytes.Buffer
+	for idx, err := range e {
+		if idx > 0 {
+			buf.WriteString("\n\n")
+		}
+		buf.WriteString(err.Error())
+	}
+	return buf.String()
+}
+
+// interpolation is a "${...}" whose expression is being scanned
+type interpolation struct {
+	start token.Position
+	// Number of unmatched "{" inside the expression
+	depth int
+}
+
+type ScannerImpl struct {
+	tokens         []*token.Token
+	errors         ScanningErrors
+	interpolations []interpolation
+	// Doc comments waiting for the next token
+	docs []string
+
+	source   string
+	startIdx int
+	startPos token.Position
+	currIdx  int
+	lineNo   int
+	// Number of runes consumed on the current line
+	column int
+}
+
+// position converts an index of the source to a position on the current line.
+// Columns are counted in runes. The index is at most a few runes behind the current one,
+// so only those are counted.
+func (s *ScannerImpl) position(idx int) token.Position {
+	column := s.column - utf8.RuneCountInString(s.source[idx:s.currIdx]) + 1
+	return token.Position{Offset: idx, Line: s.lineNo, Column: column}
+}
+
+func (s *ScannerImpl) newLine() {
+	s.lineNo += 1
+	s.column = 0
+}
+
+func (s *ScannerImpl) emit(tokenType int, literal interface{}) {
+	s.tokens = append(s.tokens, &token.Token{
+		Type:    tokenType,
+		Lexeme:  s.source[s.startIdx:s.currIdx],
+		Literal: literal,
+		Span:    token.Span{Start: s.startPos, End: s.position(s.currIdx)},
+		Doc:     strings.Join(s.docs, "\n"),
+	})
+	s.docs = nil
+}
+
+// emitError records a lexical error on the current lexeme and emits an error token for it
+func (s *ScannerImpl) emitError(reason string) {
+	s.emit(token.Error, nil)
+	s.addError(reason, s.tokens[len(s.tokens)-1].Span)
+}
+
+// addError records a lexical error located at `span`
+func (s *ScannerImpl) addError(reason string, span token.Span) {
+	lineStart := strings.LastIndexByte(s.source[:span.Start.Offset], '\n') + 1
+	lineEnd := strings.IndexByte(s.source[lineStart:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(s.source)
+	} else {
+		lineEnd += lineStart
+	}
+	s.errors = append(s.errors, &ScanningError{
+		Reason: reason,
+		Span:   span,
+		Line:   strings.TrimRight(s.source[lineStart:lineEnd], "\r"),
+	})
+}
+
+// markUnterminated flags the last error as caused by the end of the source
+func (s *ScannerImpl) markUnterminated() {
+	s.errors[len(s.errors)-1].Unterminated = true
+}
+
+func (s *ScannerImpl) emitEOF() {
+	pos := s.position(s.currIdx)
+	s.tokens = append(s.tokens, &token.Token{
+		Type: token.EOF,
+		Span: token.Span{Start: pos, End: pos},
+	})
+}
+
+func (s *ScannerImpl) hasNext() bool {
+	return s.currIdx < len(s.source)
+}
+
+// advance consumes a rune. A byte that is not valid UTF-8 is consumed as utf8.RuneError.
+func (s *ScannerImpl) advance() rune {
+	c, size := utf8.DecodeRuneInString(s.source[s.currIdx:])
+	s.currIdx += size
+	s.column += 1
+	return c
+}
+
+func (s *ScannerImpl) advanceIfMatch(expected rune) bool {
+	if !s.hasNext() {
+		return false
+	}
+	if s.peek() != expected {
+		return false
+	}
+	s.advance()
+	return true
+}
+
+func (s *ScannerImpl) peek() rune {
+	return s.peekAhead(0)
+}
+
+// peekAhead returns the rune `offset` runes after the current one
+func (s *ScannerImpl) peekAhead(offset int) rune {
+	idx := s.currIdx
+	for ; offset > 0 && idx < len(s.source); offset-- {
+		_, size := utf8.DecodeRuneInString(s.source[idx:])
+		idx += size
+	}
+	if idx >= len(s.source) {
+		return 0
+	}
+	c, _ := utf8.DecodeRuneInString(s.source[idx:])
+	return c
+}
+
+// emitString scans a string, or the rest of it after an interpolated expression when `resumed`.
+// A segment that ends with "${" is emitted as token.Interpolation (token.InterpolationMiddle
+// when resumed), followed by the tokens of the expression. The last segment of a resumed
+// string is emitted as token.InterpolationEnd.
+func (s *ScannerImpl) emitString(resumed bool) {
+	var buf strings.Builder
+	valid := true
+	for s.hasNext() && s.peek() != '"' {
+		if s.peek() == '$' && s.peekAhead(1) == '{' {
+			s.advance()
+			s.advance()
+			s.interpolations = append(s.interpolations, interpolation{start: s.position(s.currIdx - 2)})
+			if !valid {
+				s.emit(token.Error, nil)
+				return
+			}
+			if resumed {
+				s.emit(token.InterpolationMiddle, buf.String())
+			} else {
+				s.emit(token.Interpolation, buf.String())
+			}
+			return
+		}
+		charIdx := s.currIdx
+		c := s.advance()
+		switch c {
+		case '\\':
+			valid = s.scanEscape(&buf) && valid
+		case '\n':
+			s.newLine()
+			buf.WriteRune(c)
+		default:
+			// Copy from the source to keep the bytes that are not valid UTF-8
+			buf.WriteString(s.source[charIdx:s.currIdx])
+		}
+	}
+	if !s.hasNext() {
+		s.emitError("string is unterminated")
+		s.markUnterminated()
+		return
+	}
+	// Handle closing "
+	s.advance()
+	if !valid {
+		// The invalid escapes are already reported
+		s.emit(token.Error, nil)
+		return
+	}
+	if resumed {
+		s.emit(token.InterpolationEnd, buf.String())
+	} else {
+		s.emit(token.String, buf.String())
+	}
+}
+
+// scanEscape decodes the escape sequence following a backslash into `buf`.
+// Supported sequences: \n \t \r \\ \" \$ \u{1F600}
+func (s *ScannerImpl) scanEscape(buf *strings.Builder) bool {
+	startPos := s.position(s.currIdx - 1)
+	if !s.hasNext() {
+		// Let the caller report the unterminated string
+		return true
+	}
+
+	c := s.advance()
+	switch c {
+	case 'n':
+		buf.WriteByte('\n')
+	case 't':
+		buf.WriteByte('\t')
+	case 'r':
+		buf.WriteByte('\r')
+	case '\\', '"', '$':
+		buf.WriteRune(c)
+	case 'u':
+		digitsIdx := s.currIdx + 1
+		if !s.advanceIfMatch('{') {
+			s.addError("unicode escape must be in the form \\u{...}", token.Span{Start: startPos, End: s.position(s.currIdx)})
+			return false
+		}
+		for s.hasNext() && isHexDigit(s.peek()) {
+			s.advance()
+		}
+		digits := s.source[digitsIdx:s.currIdx]
+		if !s.advanceIfMatch('}') || len(digits) == 0 || len(digits) > 6 {
+			s.addError("unicode escape must be in the form \\u{...}", token.Span{Start: startPos, End: s.position(s.currIdx)})
+			return false
+		}
+		code, _ := strconv.ParseUint(digits, 16, 32)
+		if !utf8.ValidRune(rune(code)) {
+			s.addError(fmt.Sprintf("invalid unicode code point: %s", digits), token.Span{Start: startPos, End: s.position(s.currIdx)})
+			return false
+		}
+		buf.WriteRune(rune(code))
+	default:
+		endPos := s.position(s.currIdx)
+		if c == '\n' {
+			s.newLine()
+		}
+		s.addError(fmt.Sprintf("unknown escape sequence: \\%c", c), token.Span{Start: startPos, End: endPos})
+		return false
+	}
+	return true
+}
+
+// emitRawString scans a triple-quoted string. Its text is kept verbatim, including
+// line breaks and backslashes.
+func (s *ScannerImpl) emitRawString() {
+	// Consume the rest of the opening """
+	s.advance()
+	s.advance()
+	for s.hasNext() && !(s.peek() == '"' && s.peekAhead(1) == '"' && s.peekAhead(2) == '"') {
+		if s.advance() == '\n' {
+			s.newLine()
+		}
+	}
+	if !s.hasNext() {
+		s.emitError("string is unterminated")
+		s.markUnterminated()
+		return
+	}
+	// Handle closing """
+	s.advance()
+	s.advance()
+	s.advance()
+	s.emit(token.String, s.source[s.startIdx+3:s.currIdx-3])
+}
+
+// skipBlockComment skips a "/* ... */" comment, which may contain nested block comments
+func (s *ScannerImpl) skipBlockComment() {
+	depth := 1
+	for s.hasNext() && depth > 0 {
+		c := s.advance()
+		switch {
+		case c == '\n':
+			s.newLine()
+		case c == '/' && s.peek() == '*':
+			s.advance()
+			depth++
+		case c == '*' && s.peek() == '/':
+			s.advance()
+			depth--
+		}
+	}
+	if depth > 0 {
+		s.addError("block comment is unterminated", token.Span{Start: s.startPos, End: s.position(s.currIdx)})
+		s.markUnterminated()
+	}
+}
+
+func isIdentifierChar(c rune) bool {
+	return unicode.In(c, unicode.Letter, unicode.Mark, unicode.Number) || c == '_'
+}
+
+func isDigit(c rune) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isHexDigit(c rune) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+// digitValue returns the value of a hexadecimal digit, or 16 for any other rune
+func digitValue(c rune) int {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0')
+	case 'a' <= c && c <= 'f':
+		return int(c-'a') + 10
+	case 'A' <= c && c <= 'F':
+		return int(c-'A') + 10
+	}
+	return 16
+}
+
+// validSeparators reports whether every "_" of `lexeme` sits between two digits
+func validSeparators(lexeme string, isValidDigit func(rune) bool) bool {
+	for idx := 0; idx < len(lexeme); idx++ {
+		if lexeme[idx] != '_' {
+			continue
+		}
+		if idx == 0 || idx == len(lexeme)-1 || !isValidDigit(rune(lexeme[idx-1])) || !isValidDigit(rune(lexeme[idx+1])) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *ScannerImpl) emitNumber() {
+	/*
+		A number with a decimal point or an exponent is a float, otherwise it's an integer.
+		An integer that overflows int64 is a *big.Int, and a number suffixed by "d",
+		like 0.1d, is an exact decimal kept as a *big.Rat.
+		Supported formats: 1234, 1234.56, 1.5e-3, 0xFF, 0b1010, 0o17
+		Digits can be separated with "_", like 1_000_000
+	*/
+	if s.source[s.startIdx] == '0' {
+		switch s.peek() {
+		case 'x', 'X':
+			s.emitPrefixedNumber(16, "hexadecimal")
+			return
+		case 'b', 'B':
+			s.emitPrefixedNumber(2, "binary")
+			return
+		case 'o', 'O':
+			s.emitPrefixedNumber(8, "octal")
+			return
+		}
+	}
+
+	isFloat := false
+	s.skipDigits()
+	if s.peek() == '.' {
+		// Make sure there's a digit after the decimal points
+		if isDigit(s.peekAhead(1)) {
+			// Consume decimal point
+			s.advance()
+			s.skipDigits()
+			isFloat = true
+		}
+	}
+	if s.peek() == 'e' || s.peek() == 'E' {
+		isFloat = true
+		s.advance()
+		if s.peek() == '+' || s.peek() == '-' {
+			s.advance()
+		}
+		if !isDigit(s.peek()) {
+			s.emitError("exponent has no digits")
+			return
+		}
+		s.skipDigits()
+	}
+
+	lexeme := s.source[s.startIdx:s.currIdx]
+	if !validSeparators(lexeme, isDigit) {
+		s.emitError("digit separator \"_\" must be between digits")
+		return
+	}
+	lexeme = strings.ReplaceAll(lexeme, "_", "")
+	if s.peek() == 'd' && !isIdentifierChar(s.peekAhead(1)) {
+		s.advance()
+		num, _ := new(big.Rat).SetString(lexeme)
+		s.emit(token.Number, num)
+		return
+	}
+	if !isFloat {
+		s.emit(token.Number, parseInteger(lexeme, 10))
+		return
+	}
+	num, err := strconv.ParseFloat(lexeme, 64)
+	if err != nil {
+		s.emitError("number literal is out of range")
+		return
+	}
+	s.emit(token.Number, num)
+}
+
+// emitPrefixedNumber scans an integer written in `base`, after its leading "0"
+func (s *ScannerImpl) emitPrefixedNumber(base int, name string) {
+	// Consume the base prefix
+	s.advance()
+	// Take the adjacent letters too, to report them as invalid digits
+	for isDigit(s.peek()) || unicode.IsLetter(s.peek()) || s.peek() == '_' {
+		s.advance()
+	}
+
+	digits := s.source[s.startIdx+2 : s.currIdx]
+	if strings.Trim(digits, "_") == "" {
+		s.emitError(fmt.Sprintf("%s literal has no digits", name))
+		return
+	}
+	for _, c := range digits {
+		if c != '_' && digitValue(c) >= base {
+			s.emitError(fmt.Sprintf("invalid digit %q in %s literal", c, name))
+			return
+		}
+	}
+	if !validSeparators(digits, isHexDigit) {
+		s.emitError("digit separator \"_\" must be between digits")
+		return
+	}
+	s.emit(token.Number, parseInteger(strings.ReplaceAll(digits, "_", ""), base))
+}
+
+// parseInteger converts valid digits to an int64, or to a *big.Int if they overflow it
+func parseInteger(digits string, base int) interface{} {
+	if num, err := strconv.ParseInt(digits, base, 64); err == nil {
+		return num
+	}
+	num, _ := new(big.Int).SetString(digits, base)
+	return num
+}
+
+// skipDigits consumes decimal digits and digit separators
+func (s *ScannerImpl) skipDigits() {
+	for isDigit(s.peek()) || s.peek() == '_' {
+		s.advance()
+	}
+}
+
+func (s *ScannerImpl) emitIdentifier() {
+	for isIdentifierChar(s.peek()) {
+		s.advance()
+	}
+
+	name := s.source[s.startIdx:s.currIdx]
+	keywordType, found := token.ReservedWords[name]
+	if found {
+		s.emit(keywordType, nil)
+	} else {
+		s.emit(token.Identifier, nil)
+	}
+}
+
+func (s *ScannerImpl) scanToken() {
+	c := s.advance()
+
+	switch c {
+	case '(':
+		s.emit(token.LeftParen, nil)
+	case ')':
+		s.emit(token.RightParen, nil)
+	case '{':
+		if len(s.interpolations) > 0 {
+			s.interpolations[len(s.interpolations)-1].depth++
+		}
+		s.emit(token.LeftBrace, nil)
+	case '}':
+		if len(s.interpolations) > 0 {
+			top := &s.interpolations[len(s.interpolations)-1]
+			if top.depth == 0 {
+				// End of an interpolated expression, resume the string
+				s.interpolations = s.interpolations[:len(s.interpolations)-1]
+				s.emitString(true)
+				return
+			}
+			top.depth--
+		}
+		s.emit(token.RightBrace, nil)
+	case '[':
+		s.emit(token.LeftBracket, nil)
+	case ']':
+		s.emit(token.RightBracket, nil)
+	case ':':
+		s.emit(token.Colon, nil)
+	case ',':
+		s.emit(token.Comma, nil)
+	case '.':
+		s.emit(token.Dot, nil)
+	case '-':
+		if s.advanceIfMatch('-') {
+			s.emit(token.MinusMinus, nil)
+		} else if s.advanceIfMatch('=') {
+			s.emit(token.MinusEqual, nil)
+		} else {
+			s.emit(token.Minus, nil)
+		}
+	case '+':
+		if s.advanceIfMatch('+') {
+			s.emit(token.PlusPlus, nil)
+		} else if s.advanceIfMatch('=') {
+			s.emit(token.PlusEqual, nil)
+		} else {
+			s.emit(token.Plus, nil)
+		}
+	case ';':
+		s.emit(token.SemiColon, nil)
+	case '*':
+		if s.advanceIfMatch('*') {
+			s.emit(token.StarStar, nil)
+		} else if s.advanceIfMatch('=') {
+			s.emit(token.StarEqual, nil)
+		} else {
+			s.emit(token.Star, nil)
+		}
+	case '%':
+		if s.advanceIfMatch('=') {
+			s.emit(token.PercentEqual, nil)
+		} else {
+			s.emit(token.Percent, nil)
+		}
+	case '&':
+		s.emit(token.Ampersand, nil)
+	case '|':
+		s.emit(token.Pipe, nil)
+	case '^':
+		s.emit(token.Caret, nil)
+	case '~':
+		s.emit(token.Tilde, nil)
+	case '!':
+		if s.advanceIfMatch('=') {
+			s.emit(token.BangEqual, nil)
+		} else {
+			s.emit(token.Bang, nil)
+		}
+	case '=':
+		if s.advanceIfMatch('=') {
+			s.emit(token.EqualEqual, nil)
+		} else {
+			s.emit(token.Equal, nil)
+		}
+	case '<':
+		if s.advanceIfMatch('=') {
+			s.emit(token.LessEqual, nil)
+		} else if s.advanceIfMatch('<') {
+			s.emit(token.LessLess, nil)
+		} else {
+			s.emit(token.Less, nil)
+		}
+	case '>':
+		if s.advanceIfMatch('=') {
+			s.emit(token.GreaterEqual, nil)
+		} else if s.advanceIfMatch('>') {
+			s.emit(token.GreaterGreater, nil)
+		} else {
+			s.emit(token.Greater, nil)
+		}
+	case '/':
+		if s.advanceIfMatch('/') {
+			// Handle comment
+			isDoc := s.peek() == '/' && s.peekAhead(1) != '/'
+			for s.hasNext() && s.peek() != '\n' {
+				s.advance()
+			}
+			if isDoc {
+				doc := strings.TrimPrefix(s.source[s.startIdx+3:s.currIdx], " ")
+				s.docs = append(s.docs, strings.TrimRight(doc, "\r"))
+			}
+		} else if s.advanceIfMatch('*') {
+			s.skipBlockComment()
+		} else if s.advanceIfMatch('=') {
+			s.emit(token.SlashEqual, nil)
+		} else {
+			s.emit(token.Slash, nil)
+		}
+	case '"':
+		if s.peek() == '"' && s.peekAhead(1) == '"' {
+			s.emitRawString()
+		} else {
+			s.emitString(false)
+		}
+	case ' ':
+		fallthrough
+	case '\t':
+		fallthrough
+	case '\r':
+		// Do nothing
+	case '\n':
+		s.newLine()
+	default:
+		if isDigit(c) {
+			s.emitNumber()
+		} else if unicode.IsLetter(c) || c == '_' {
+			s.emitIdentifier()
+		} else if c == utf8.RuneError && s.currIdx-s.startIdx == 1 {
+			s.emitError("invalid UTF-8 encoding")
+		} else {
+			s.emitError(fmt.Sprintf("unrecognized token: %c", c))
+		}
+	}
+}
+
+func (s *ScannerImpl) reset(source string) {
+	s.tokens = []*token.Token{}
+	s.errors = nil
+	s.interpolations = nil
+	s.docs = nil
+	s.source = source
+	s.lineNo = 1
+	s.column = 0
+	s.currIdx = 0
+}
+
+// Scan splits a source into tokens. It goes on after a lexical error, so that
+// all of them are reported together as a ScanningErrors. The malformed lexemes
+// are kept in the returned tokens as token.Error.
+func (s *ScannerImpl) Scan(source string) ([]*token.Token, error) {
+	s.reset(source)
+	for s.hasNext() {
+		s.startIdx = s.currIdx
+		s.startPos = s.position(s.currIdx)
+		s.scanToken()
+	}
+	for _, interp := range s.interpolations {
+		s.addError("interpolation is unterminated", token.Span{Start: interp.start, End: s.position(s.currIdx)})
+		s.markUnterminated()
+	}
+	s.emitEOF()
+	if len(s.errors) > 0 {
+		return s.tokens, s.errors
+	}
+	return s.tokens, nil
+}
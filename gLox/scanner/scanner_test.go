@@ -0,0 +1,382 @@
+package scanner
+
+import (
+	"fmt"
+	"github.com/StanleyGY/Lox/gLox/token"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanner(t *testing.T) {
+	t.Run("Test non-alpha lexeme", func(t *testing.T) {
+		scanner := ScannerImpl{}
+		scanner.Scan("!*+-/ =<>!=")
+
+		expectedTypes := []int{
+			token.Bang,
+			token.Star,
+			token.Plus,
+			token.Minus,
+			token.Slash,
+			token.Equal,
+			token.Less,
+			token.Greater,
+			token.BangEqual,
+			token.EOF,
+		}
+		for idx := range scanner.tokens {
+			assert.Equal(t, expectedTypes[idx], scanner.tokens[idx].Type)
+		}
+	})
+
+	t.Run("Test comments", func(t *testing.T) {
+		scanner := ScannerImpl{}
+		scanner.Scan("// this is a comment")
+
+		expectedTypes := []int{
+			token.EOF,
+		}
+		for idx := range scanner.tokens {
+			assert.Equal(t, expectedTypes[idx], scanner.tokens[idx].Type)
+		}
+	})
+
+	t.Run("Test multi-line", func(t *testing.T) {
+		scanner := ScannerImpl{}
+		scanner.Scan("/ \n // this is a comment")
+
+		expectedTypes := []int{
+			token.Slash,
+			token.EOF,
+		}
+		for idx := range scanner.tokens {
+			assert.Equal(t, expectedTypes[idx], scanner.tokens[idx].Type)
+		}
+	})
+
+	t.Run("Test string literal", func(t *testing.T) {
+		literal := "this is a string literal"
+
+		scanner := ScannerImpl{}
+		tokens, _ := scanner.Scan(fmt.Sprintf("\"%s\"", literal))
+
+		assert.Equal(t, token.String, tokens[0].Type)
+		assert.Equal(t, tokens[0].Literal, literal)
+
+		_, err := scanner.Scan("\"unterminated")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test numbers", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, _ := scanner.Scan("132")
+		assert.Equal(t, token.Number, tokens[0].Type)
+		assert.Equal(t, int64(132), tokens[0].Literal)
+
+		tokens, _ = scanner.Scan("132.45")
+		assert.Equal(t, token.Number, tokens[0].Type)
+		assert.Equal(t, 132.45, tokens[0].Literal)
+
+		tokens, _ = scanner.Scan("132.")
+		assert.Equal(t, token.Number, tokens[0].Type)
+		assert.Equal(t, int64(132), tokens[0].Literal)
+	})
+
+	t.Run("Test identifiers", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, _ := scanner.Scan("var _my_var")
+		assert.Equal(t, token.Var, tokens[0].Type)
+		assert.Equal(t, token.Identifier, tokens[1].Type)
+		assert.Equal(t, "_my_var", tokens[1].Lexeme)
+
+		tokens, _ = scanner.Scan("if else")
+		assert.Equal(t, token.If, tokens[0].Type)
+		assert.Equal(t, token.Else, tokens[1].Type)
+
+		tokens, _ = scanner.Scan("in index")
+		assert.Equal(t, token.In, tokens[0].Type)
+		assert.Equal(t, token.Identifier, tokens[1].Type)
+
+		tokens, _ = scanner.Scan("break continue")
+		assert.Equal(t, token.Break, tokens[0].Type)
+		assert.Equal(t, token.Continue, tokens[1].Type)
+	})
+	t.Run("Test positions", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, _ := scanner.Scan("var a =\n  12;")
+		assert.Equal(t, token.Position{Offset: 0, Line: 1, Column: 1}, tokens[0].Span.Start)
+		assert.Equal(t, token.Position{Offset: 3, Line: 1, Column: 4}, tokens[0].Span.End)
+		assert.Equal(t, token.Position{Offset: 10, Line: 2, Column: 3}, tokens[3].Span.Start)
+		assert.Equal(t, token.Position{Offset: 12, Line: 2, Column: 5}, tokens[3].Span.End)
+
+		// EOF is positioned at the end of the source
+		assert.Equal(t, token.EOF, tokens[5].Type)
+		assert.Equal(t, token.Position{Offset: 13, Line: 2, Column: 6}, tokens[5].Span.Start)
+	})
+
+	t.Run("Test lexical errors", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, err := scanner.Scan("var a = #;\nprint @ 1;\n\"open")
+		errs := err.(ScanningErrors)
+		assert.Equal(t, 3, len(errs))
+		assert.Equal(t, token.Position{Offset: 8, Line: 1, Column: 9}, errs[0].Span.Start)
+		assert.Equal(t, "unrecognized token: #", errs[0].Reason)
+		assert.Equal(t, token.Position{Offset: 17, Line: 2, Column: 7}, errs[1].Span.Start)
+		assert.Equal(t, "string is unterminated", errs[2].Reason)
+		assert.Equal(t, "Scanning failed at line 2, column 7: \nprint @ 1;\n      ^\nFailed reason: unrecognized token: @", errs[1].Error())
+		assert.Equal(t, "Scanning failed at line 3, column 1: \n\"open\n^^^^^\nFailed reason: string is unterminated", errs[2].Error())
+
+		// Scanning goes on after the errors
+		assert.Equal(t, token.Error, tokens[3].Type)
+		assert.Equal(t, token.Number, tokens[7].Type)
+		assert.Equal(t, token.Error, tokens[9].Type)
+		assert.Equal(t, token.EOF, tokens[10].Type)
+	})
+
+	t.Run("Test string escapes", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, err := scanner.Scan(`"a\nb\tc\rd\\e\"f\u{1F600}\u{41}"`)
+		assert.NoError(t, err)
+		assert.Equal(t, "a\nb\tc\rd\\e\"f\U0001F600A", tokens[0].Literal)
+
+		tokens, err = scanner.Scan(`"\q" "\u{110000}" "\u41" 1`)
+		errs := err.(ScanningErrors)
+		assert.Equal(t, 3, len(errs))
+		assert.Equal(t, "unknown escape sequence: \\q", errs[0].Reason)
+		assert.Equal(t, token.Position{Offset: 1, Line: 1, Column: 2}, errs[0].Span.Start)
+		assert.Equal(t, "invalid unicode code point: 110000", errs[1].Reason)
+		assert.Equal(t, "unicode escape must be in the form \\u{...}", errs[2].Reason)
+		assert.Equal(t, token.Error, tokens[0].Type)
+		assert.Equal(t, token.Number, tokens[3].Type)
+	})
+
+	t.Run("Test multi-line strings", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, _ := scanner.Scan("\"a\nb\" x")
+		assert.Equal(t, "a\nb", tokens[0].Literal)
+		assert.Equal(t, token.Position{Offset: 6, Line: 2, Column: 4}, tokens[1].Span.Start)
+
+		tokens, err := scanner.Scan("\"\"\"line \\n\n  \"quoted\" \"\"\" x")
+		assert.NoError(t, err)
+		assert.Equal(t, "line \\n\n  \"quoted\" ", tokens[0].Literal)
+		assert.Equal(t, token.Position{Offset: 26, Line: 2, Column: 16}, tokens[1].Span.Start)
+
+		_, err = scanner.Scan("\"\"\"open\"\"")
+		assert.Equal(t, "string is unterminated", err.(ScanningErrors)[0].Reason)
+	})
+
+	t.Run("Test string interpolation", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, err := scanner.Scan(`"a ${b + "${c}"} d ${ {} } \${e}"`)
+		assert.NoError(t, err)
+		expectedTypes := []int{
+			token.Interpolation,
+			token.Identifier,
+			token.Plus,
+			token.Interpolation,
+			token.Identifier,
+			token.InterpolationEnd,
+			token.InterpolationMiddle,
+			token.LeftBrace,
+			token.RightBrace,
+			token.InterpolationEnd,
+			token.EOF,
+		}
+		for idx := range tokens {
+			assert.Equal(t, expectedTypes[idx], tokens[idx].Type)
+		}
+		assert.Equal(t, "a ", tokens[0].Literal)
+		assert.Equal(t, "", tokens[5].Literal)
+		assert.Equal(t, " d ", tokens[6].Literal)
+		assert.Equal(t, " ${e}", tokens[9].Literal)
+
+		_, err = scanner.Scan(`"a ${b`)
+		assert.Equal(t, "interpolation is unterminated", err.(ScanningErrors)[0].Reason)
+	})
+
+	t.Run("Test block comments", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, err := scanner.Scan("a /* one\n /* nested\n */ still */ b / c")
+		assert.NoError(t, err)
+		expectedTypes := []int{
+			token.Identifier,
+			token.Identifier,
+			token.Slash,
+			token.Identifier,
+			token.EOF,
+		}
+		for idx := range tokens {
+			assert.Equal(t, expectedTypes[idx], tokens[idx].Type)
+		}
+		assert.Equal(t, token.Position{Offset: 33, Line: 3, Column: 14}, tokens[1].Span.Start)
+
+		_, err = scanner.Scan("a /* /* */")
+		assert.Equal(t, "block comment is unterminated", err.(ScanningErrors)[0].Reason)
+	})
+
+	t.Run("Test doc comments", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, _ := scanner.Scan("/// Adds two numbers.\n///\n///Returns a number.\n//// not a doc\nfun add")
+		assert.Equal(t, "Adds two numbers.\n\nReturns a number.", tokens[0].Doc)
+		assert.Equal(t, "", tokens[1].Doc)
+	})
+
+	t.Run("Test unicode", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, err := scanner.Scan("var café = \"日本語 😀\"; print naïve_π2 + x̃;")
+		assert.NoError(t, err)
+		assert.Equal(t, token.Identifier, tokens[1].Type)
+		assert.Equal(t, "café", tokens[1].Lexeme)
+		assert.Equal(t, token.Position{Offset: 10, Line: 1, Column: 10}, tokens[2].Span.Start)
+		assert.Equal(t, "日本語 😀", tokens[3].Literal)
+		assert.Equal(t, token.Position{Offset: 28, Line: 1, Column: 19}, tokens[3].Span.End)
+		assert.Equal(t, "naïve_π2", tokens[6].Lexeme)
+		assert.Equal(t, "x̃", tokens[8].Lexeme)
+
+		_, err = scanner.Scan("var ü = 1 → 2;")
+		errs := err.(ScanningErrors)
+		assert.Equal(t, "unrecognized token: →", errs[0].Reason)
+		assert.Equal(t, token.Position{Offset: 11, Line: 1, Column: 11}, errs[0].Span.Start)
+		assert.Equal(t, "Scanning failed at line 1, column 11: \nvar ü = 1 → 2;\n          ^\nFailed reason: unrecognized token: →", errs[0].Error())
+
+		_, err = scanner.Scan("a \xff b")
+		assert.Equal(t, "invalid UTF-8 encoding", err.(ScanningErrors)[0].Reason)
+	})
+
+	t.Run("Test numeric literal forms", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, err := scanner.Scan("0xFF 0XfF 0b1010 0o17 1_000_000 1.5e-3 2E+2 3e2 0 0.5 12.")
+		assert.NoError(t, err)
+		expected := []interface{}{int64(255), int64(255), int64(10), int64(15), int64(1000000), 0.0015, 200.0, 300.0, int64(0), 0.5, int64(12)}
+		for idx, num := range expected {
+			assert.Equal(t, token.Number, tokens[idx].Type)
+			assert.Equal(t, num, tokens[idx].Literal)
+		}
+		assert.Equal(t, token.Dot, tokens[len(expected)].Type)
+
+		tokens, err = scanner.Scan("9223372036854775808 0x1_0000_0000_0000_0000 0.1d 12d 1.5e-3d 1dx")
+		assert.NoError(t, err)
+		assert.Equal(t, "9223372036854775808", tokens[0].Literal.(*big.Int).String())
+		assert.Equal(t, "18446744073709551616", tokens[1].Literal.(*big.Int).String())
+		assert.Equal(t, "1/10", tokens[2].Literal.(*big.Rat).RatString())
+		assert.Equal(t, "12", tokens[3].Literal.(*big.Rat).RatString())
+		assert.Equal(t, "3/2000", tokens[4].Literal.(*big.Rat).RatString())
+		assert.Equal(t, int64(1), tokens[5].Literal)
+		assert.Equal(t, "dx", tokens[6].Lexeme)
+
+		for src, reason := range map[string]string{
+			"0x":    "hexadecimal literal has no digits",
+			"0b":    "binary literal has no digits",
+			"0b102": "invalid digit '2' in binary literal",
+			"0o8":   "invalid digit '8' in octal literal",
+			"0xFG":  "invalid digit 'G' in hexadecimal literal",
+			"1__0":  "digit separator \"_\" must be between digits",
+			"1_":    "digit separator \"_\" must be between digits",
+			"1_.5":  "digit separator \"_\" must be between digits",
+			"0x_ff": "digit separator \"_\" must be between digits",
+			"1e":    "exponent has no digits",
+			"1e+":   "exponent has no digits",
+			"1e999": "number literal is out of range",
+		} {
+			tokens, err := scanner.Scan(src + " a")
+			errs := err.(ScanningErrors)
+			assert.Equal(t, 1, len(errs), src)
+			assert.Equal(t, reason, errs[0].Reason, src)
+			assert.Equal(t, token.Error, tokens[0].Type, src)
+			assert.Equal(t, token.Identifier, tokens[1].Type, src)
+		}
+	})
+
+	t.Run("Test operators", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, _ := scanner.Scan("% ** * & | ^ ~ << <= < >> >= >")
+		expectedTypes := []int{
+			token.Percent,
+			token.StarStar,
+			token.Star,
+			token.Ampersand,
+			token.Pipe,
+			token.Caret,
+			token.Tilde,
+			token.LessLess,
+			token.LessEqual,
+			token.Less,
+			token.GreaterGreater,
+			token.GreaterEqual,
+			token.Greater,
+			token.EOF,
+		}
+		for idx := range tokens {
+			assert.Equal(t, expectedTypes[idx], tokens[idx].Type)
+		}
+	})
+
+	t.Run("Test update operators", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, _ := scanner.Scan("+= -= *= /= %= ++ -- + - * / % **")
+		expectedTypes := []int{
+			token.PlusEqual,
+			token.MinusEqual,
+			token.StarEqual,
+			token.SlashEqual,
+			token.PercentEqual,
+			token.PlusPlus,
+			token.MinusMinus,
+			token.Plus,
+			token.Minus,
+			token.Star,
+			token.Slash,
+			token.Percent,
+			token.StarStar,
+			token.EOF,
+		}
+		for idx := range tokens {
+			assert.Equal(t, expectedTypes[idx], tokens[idx].Type)
+		}
+	})
+
+	t.Run("Test brackets", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		tokens, _ := scanner.Scan("xs[1:]")
+		expectedTypes := []int{
+			token.Identifier,
+			token.LeftBracket,
+			token.Number,
+			token.Colon,
+			token.RightBracket,
+			token.EOF,
+		}
+		for idx := range tokens {
+			assert.Equal(t, expectedTypes[idx], tokens[idx].Type)
+		}
+	})
+
+	t.Run("Test columns on a long line", func(t *testing.T) {
+		scanner := ScannerImpl{}
+
+		// Each "é" is a single column, though it's two bytes
+		src := strings.Repeat("é", 5000) + " \"${x}\\q\" y"
+		tokens, err := scanner.Scan(src)
+		assert.Equal(t, token.Position{Offset: 10001, Line: 1, Column: 5002}, tokens[1].Span.Start)
+		assert.Equal(t, token.Position{Offset: 10005, Line: 1, Column: 5006}, tokens[3].Span.Start)
+		assert.Equal(t, token.Position{Offset: 10006, Line: 1, Column: 5007}, err.(ScanningErrors)[0].Span.Start)
+		assert.Equal(t, token.Position{Offset: 10010, Line: 1, Column: 5011}, tokens[4].Span.Start)
+	})
+}
@@ -0,0 +1,35 @@
+package token
+
+import (
+	"math/big"
+	"strings"
+)
+
+// DecimalPrecision is the number of fractional digits shown for a decimal
+// that has no finite expansion, like 1/3
+const DecimalPrecision = 32
+
+// FormatDecimal writes a decimal number, which is kept as a *big.Rat, in positional notation
+func FormatDecimal(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	// A fraction has a finite expansion iff its denominator is of the form 2^a * 5^b,
+	// and the expansion has max(a, b) digits
+	denom := new(big.Int).Set(r.Denom())
+	two, five := big.NewInt(2), big.NewInt(5)
+	twos, fives := 0, 0
+	for new(big.Int).Rem(denom, two).Sign() == 0 {
+		denom.Quo(denom, two)
+		twos++
+	}
+	for new(big.Int).Rem(denom, five).Sign() == 0 {
+		denom.Quo(denom, five)
+		fives++
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		str := strings.TrimRight(r.FloatString(DecimalPrecision), "0")
+		return strings.TrimSuffix(str, ".")
+	}
+	return r.FloatString(max(twos, fives))
+}
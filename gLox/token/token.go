@@ -0,0 +1,147 @@
+package token
+
+import "fmt"
+
+const (
+	// Non-alpha symbols
+	LeftParen = iota
+	RightParen
+	LeftBrace
+	RightBrace
+	LeftBracket
+	RightBracket
+	Colon
+	Comma
+	Dot
+	Minus
+	Plus
+	SemiColon
+	Slash
+	Star
+	Percent
+	Bang
+	BangEqual
+	Equal
+	EqualEqual
+	Greater
+	GreaterEqual
+	Less
+	LessEqual
+	StarStar
+	Ampersand
+	Pipe
+	Caret
+	Tilde
+	LessLess
+	GreaterGreater
+	PlusEqual
+	MinusEqual
+	StarEqual
+	SlashEqual
+	PercentEqual
+	PlusPlus
+	MinusMinus
+
+	// Literals
+	Identifier
+	String
+	// String segment that precedes an interpolated expression "${...}"
+	Interpolation
+	// String segment between two interpolated expressions, from a "}" to a "${"
+	InterpolationMiddle
+	// String segment after the last interpolated expression, from a "}" to the closing quote
+	InterpolationEnd
+	Number
+
+	// Reserved words
+	And
+	Class
+	Else
+	False
+	Fun
+	For
+	If
+	In
+	Nil
+	Or
+	Print
+	Return
+	Break
+	Continue
+	Super
+	This
+	True
+	Var
+	While
+
+	// Malformed lexeme, reported by the scanner
+	Error
+
+	EOF
+)
+
+var ReservedWords = map[string]int{
+	"and":      And,
+	"class":    Class,
+	"else":     Else,
+	"false":    False,
+	"fun":      Fun,
+	"for":      For,
+	"if":       If,
+	"in":       In,
+	"nil":      Nil,
+	"or":       Or,
+	"print":    Print,
+	"return":   Return,
+	"break":    Break,
+	"continue": Continue,
+	"super":    Super,
+	"this":     This,
+	"true":     True,
+	"var":      Var,
+	"while":    While,
+}
+
+// Position is a location in the source text
+type Position struct {
+	// Byte offset, starting at 0
+	Offset int
+	// Line number, starting at 1
+	Line int
+	// Column number, starting at 1
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Span is a range of the source text. `End` is exclusive.
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// Join returns the smallest span that covers both `s` and `other`
+func (s Span) Join(other Span) Span {
+	if other.Start.Offset < s.Start.Offset {
+		s.Start = other.Start
+	}
+	if other.End.Offset > s.End.Offset {
+		s.End = other.End
+	}
+	return s
+}
+
+type Token struct {
+	Type    int
+	Lexeme  string
+	Literal interface{}
+	Span    Span
+	// Text of the "///" doc comments right before the token, one line per comment
+	Doc string
+}
+
+func (t Token) ToString() string {
+	return fmt.Sprintf("%d %s", t.Type, t.Lexeme)
+}